@@ -0,0 +1,75 @@
+// Package augment provides random, seedable training-time transformations of
+// image batches stored as channel-major rows, as consumed by layer.Conv.
+package augment
+
+import (
+	"math/rand/v2"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Shape is the channel-major layout of every row of a batch.
+type Shape struct {
+	Channels   int
+	Rows, Cols int
+}
+
+func (s Shape) channelSize() int {
+	return s.Rows * s.Cols
+}
+
+// Augmentation modifies a batch in place. Most augmentations only touch x;
+// mixing augmentations also blend the targets in y and the per-sample
+// weights in w, which is nil for unweighted batches.
+type Augmentation interface {
+	Augment(x, y *mat.Dense, w []float64, rng *rand.Rand)
+}
+
+// Pipeline applies its steps in order to copies of every training batch.
+type Pipeline struct {
+	Steps []Augmentation
+
+	rng *rand.Rand
+}
+
+// NewPipeline returns a pipeline whose randomness is fully determined by seed.
+func NewPipeline(seed uint64, steps ...Augmentation) *Pipeline {
+	return &Pipeline{
+		Steps: steps,
+		rng:   rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+// Apply returns augmented copies of x, y and the sample weights w, leaving
+// the originals untouched. w may be nil.
+func (p *Pipeline) Apply(x, y *mat.Dense, w []float64) (*mat.Dense, *mat.Dense, []float64) {
+	outX, outY := mat.DenseCopyOf(x), mat.DenseCopyOf(y)
+	var outW []float64
+	if w != nil {
+		outW = append([]float64(nil), w...)
+	}
+	for _, step := range p.Steps {
+		step.Augment(outX, outY, outW, p.rng)
+	}
+	return outX, outY, outW
+}
+
+// shift moves every channel of row by dy rows and dx columns, filling the
+// uncovered area with zeros.
+func shift(row []float64, shape Shape, dy, dx int) {
+	src := append([]float64(nil), row...)
+	size := shape.channelSize()
+	for c := 0; c < shape.Channels; c++ {
+		offset := c * size
+		for i := 0; i < shape.Rows; i++ {
+			for j := 0; j < shape.Cols; j++ {
+				si, sj := i-dy, j-dx
+				v := 0.0
+				if si >= 0 && si < shape.Rows && sj >= 0 && sj < shape.Cols {
+					v = src[offset+si*shape.Cols+sj]
+				}
+				row[offset+i*shape.Cols+j] = v
+			}
+		}
+	}
+}
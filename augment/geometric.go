@@ -0,0 +1,150 @@
+package augment
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// RandomCrop pads every image with Padding zeros on each side and crops a
+// random window of the original size, which shifts the image by up to
+// Padding pixels in each direction.
+type RandomCrop struct {
+	Shape   Shape
+	Padding int
+}
+
+func NewRandomCrop(shape Shape, padding int) *RandomCrop {
+	return &RandomCrop{Shape: shape, Padding: padding}
+}
+
+func (a *RandomCrop) Augment(x, _ *mat.Dense, _ []float64, rng *rand.Rand) {
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		dy := rng.IntN(2*a.Padding+1) - a.Padding
+		dx := rng.IntN(2*a.Padding+1) - a.Padding
+		shift(x.RawRowView(i), a.Shape, dy, dx)
+	}
+}
+
+// RandomTranslation shifts every image by a random fraction of its size of
+// at most MaxFraction in each direction.
+type RandomTranslation struct {
+	Shape       Shape
+	MaxFraction float64
+}
+
+func NewRandomTranslation(shape Shape, maxFraction float64) *RandomTranslation {
+	return &RandomTranslation{Shape: shape, MaxFraction: maxFraction}
+}
+
+func (a *RandomTranslation) Augment(x, _ *mat.Dense, _ []float64, rng *rand.Rand) {
+	maxDy := int(a.MaxFraction * float64(a.Shape.Rows))
+	maxDx := int(a.MaxFraction * float64(a.Shape.Cols))
+
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		dy := rng.IntN(2*maxDy+1) - maxDy
+		dx := rng.IntN(2*maxDx+1) - maxDx
+		shift(x.RawRowView(i), a.Shape, dy, dx)
+	}
+}
+
+// Flip mirrors images with probability P, horizontally (left-right) or
+// vertically (top-bottom).
+type Flip struct {
+	Shape      Shape
+	P          float64
+	Horizontal bool
+}
+
+func NewHorizontalFlip(shape Shape, p float64) *Flip {
+	return &Flip{Shape: shape, P: p, Horizontal: true}
+}
+
+func NewVerticalFlip(shape Shape, p float64) *Flip {
+	return &Flip{Shape: shape, P: p}
+}
+
+func (a *Flip) Augment(x, _ *mat.Dense, _ []float64, rng *rand.Rand) {
+	size := a.Shape.channelSize()
+	cols := a.Shape.Cols
+
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		if rng.Float64() >= a.P {
+			continue
+		}
+		row := x.RawRowView(i)
+		for c := 0; c < a.Shape.Channels; c++ {
+			channel := row[c*size : (c+1)*size]
+			if a.Horizontal {
+				for y := 0; y < a.Shape.Rows; y++ {
+					line := channel[y*cols : (y+1)*cols]
+					for l, rr := 0, cols-1; l < rr; l, rr = l+1, rr-1 {
+						line[l], line[rr] = line[rr], line[l]
+					}
+				}
+				continue
+			}
+			for top, bottom := 0, a.Shape.Rows-1; top < bottom; top, bottom = top+1, bottom-1 {
+				for xx := 0; xx < cols; xx++ {
+					channel[top*cols+xx], channel[bottom*cols+xx] = channel[bottom*cols+xx], channel[top*cols+xx]
+				}
+			}
+		}
+	}
+}
+
+// RandomRotation rotates every image about its centre by a random angle of
+// at most MaxDegrees in either direction, sampling bilinearly and filling
+// the corners with zeros.
+type RandomRotation struct {
+	Shape      Shape
+	MaxDegrees float64
+}
+
+func NewRandomRotation(shape Shape, maxDegrees float64) *RandomRotation {
+	return &RandomRotation{Shape: shape, MaxDegrees: maxDegrees}
+}
+
+func (a *RandomRotation) Augment(x, _ *mat.Dense, _ []float64, rng *rand.Rand) {
+	size := a.Shape.channelSize()
+	rows, cols := a.Shape.Rows, a.Shape.Cols
+	cy, cx := float64(rows-1)/2, float64(cols-1)/2
+
+	at := func(channel []float64, y, x int) float64 {
+		if y < 0 || y >= rows || x < 0 || x >= cols {
+			return 0
+		}
+		return channel[y*cols+x]
+	}
+
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		angle := (rng.Float64()*2 - 1) * a.MaxDegrees * math.Pi / 180
+		sin, cos := math.Sincos(angle)
+
+		row := x.RawRowView(i)
+		src := append([]float64(nil), row...)
+		for c := 0; c < a.Shape.Channels; c++ {
+			channel := src[c*size : (c+1)*size]
+			for y := 0; y < rows; y++ {
+				for xx := 0; xx < cols; xx++ {
+					// inverse rotation maps each output pixel to its source
+					dy, dx := float64(y)-cy, float64(xx)-cx
+					sy := cos*dy - sin*dx + cy
+					sx := sin*dy + cos*dx + cx
+
+					y0, x0 := int(math.Floor(sy)), int(math.Floor(sx))
+					fy, fx := sy-float64(y0), sx-float64(x0)
+					row[c*size+y*cols+xx] = (1-fy)*(1-fx)*at(channel, y0, x0) +
+						(1-fy)*fx*at(channel, y0, x0+1) +
+						fy*(1-fx)*at(channel, y0+1, x0) +
+						fy*fx*at(channel, y0+1, x0+1)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package augment
+
+import (
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ramp returns a batch of n images of shape whose pixels hold distinct values.
+func ramp(n int, shape Shape) *mat.Dense {
+	x := mat.NewDense(n, shape.Channels*shape.channelSize(), nil)
+	for i, data := 0, x.RawMatrix().Data; i < len(data); i++ {
+		data[i] = float64(i + 1)
+	}
+	return x
+}
+
+// shiftOf returns the offset, within maxShift in each direction, by which
+// shift turns orig into got.
+func shiftOf(orig, got []float64, shape Shape, maxShift int) (dy, dx int, ok bool) {
+	for dy := -maxShift; dy <= maxShift; dy++ {
+		for dx := -maxShift; dx <= maxShift; dx++ {
+			want := append([]float64(nil), orig...)
+			shift(want, shape, dy, dx)
+			if slices.Equal(got, want) {
+				return dy, dx, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func TestShift(t *testing.T) {
+	shape := Shape{Channels: 2, Rows: 2, Cols: 3}
+	row := []float64{
+		1, 2, 3,
+		4, 5, 6,
+
+		7, 8, 9,
+		10, 11, 12,
+	}
+	// one row down and one column left
+	shift(row, shape, 1, -1)
+	want := []float64{
+		0, 0, 0,
+		2, 3, 0,
+
+		0, 0, 0,
+		8, 9, 0,
+	}
+	if !slices.Equal(row, want) {
+		t.Errorf("shifted row %v, want %v", row, want)
+	}
+}
+
+func TestRandomShifts(t *testing.T) {
+	shape := Shape{Channels: 2, Rows: 4, Cols: 4}
+	tests := []struct {
+		name     string
+		step     Augmentation
+		maxShift int
+	}{
+		{"crop", NewRandomCrop(shape, 1), 1},
+		{"crop without padding", NewRandomCrop(shape, 0), 0},
+		// half of 4 pixels
+		{"translation", NewRandomTranslation(shape, 0.5), 2},
+		// a fifth of 4 pixels rounds down to no shift
+		{"small translation", NewRandomTranslation(shape, 0.2), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := ramp(20, shape)
+			out, _, _ := NewPipeline(3, tt.step).Apply(x, x, nil)
+
+			shifted := false
+			for i := 0; i < 20; i++ {
+				dy, dx, ok := shiftOf(x.RawRowView(i), out.RawRowView(i), shape, tt.maxShift)
+				if !ok {
+					t.Fatalf("image %d is not shifted by at most %d pixels: %v", i, tt.maxShift, out.RawRowView(i))
+				}
+				shifted = shifted || dy != 0 || dx != 0
+			}
+			if shifted != (tt.maxShift > 0) {
+				t.Errorf("some image shifted = %v, want %v", shifted, tt.maxShift > 0)
+			}
+		})
+	}
+}
+
+func TestFlip(t *testing.T) {
+	shape := Shape{Channels: 2, Rows: 2, Cols: 3}
+	x := mat.NewDense(1, 12, []float64{
+		1, 2, 3,
+		4, 5, 6,
+
+		7, 8, 9,
+		10, 11, 12,
+	})
+	tests := []struct {
+		name string
+		step *Flip
+		want []float64
+	}{
+		{"horizontal", NewHorizontalFlip(shape, 1), []float64{
+			3, 2, 1,
+			6, 5, 4,
+
+			9, 8, 7,
+			12, 11, 10,
+		}},
+		{"vertical", NewVerticalFlip(shape, 1), []float64{
+			4, 5, 6,
+			1, 2, 3,
+
+			10, 11, 12,
+			7, 8, 9,
+		}},
+		{"never", NewHorizontalFlip(shape, 0), x.RawRowView(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, _ := NewPipeline(1, tt.step).Apply(x, x, nil)
+			if got := out.RawRowView(0); !slices.Equal(got, tt.want) {
+				t.Errorf("flipped row %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRandomRotationWithoutAngle(t *testing.T) {
+	shape := Shape{Channels: 1, Rows: 3, Cols: 3}
+	x := ramp(2, shape)
+	out, _, _ := NewPipeline(1, NewRandomRotation(shape, 0)).Apply(x, x, nil)
+	if !mat.EqualApprox(out, x, 1e-12) {
+		t.Errorf("rotation by 0 degrees changed the batch: %v", mat.Formatted(out))
+	}
+}
+
+func TestFillBox(t *testing.T) {
+	shape := Shape{Channels: 2, Rows: 3, Cols: 3}
+	tests := []struct {
+		name             string
+		top, left, h, w  int
+		wantCovered      int
+		wantFilledPixels []int
+	}{
+		{"inside", 0, 1, 2, 2, 4, []int{1, 2, 4, 5}},
+		// a 3x3 box centred on the top-right corner keeps its bottom-left 2x2
+		{"clipped", -1, 1, 3, 3, 4, []int{1, 2, 4, 5}},
+		{"clipped on every side", -5, -5, 20, 20, 9, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}},
+		{"outside", 3, 0, 2, 2, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := make([]float64, 18)
+			src := make([]float64, 18)
+			for i := range src {
+				src[i] = float64(i + 1)
+			}
+			if covered := fillBox(dst, src, shape, tt.top, tt.left, tt.h, tt.w); covered != tt.wantCovered {
+				t.Errorf("covered %d pixels, want %d", covered, tt.wantCovered)
+			}
+
+			// both channels are filled at the same pixels
+			want := make([]float64, 18)
+			for _, p := range tt.wantFilledPixels {
+				want[p], want[9+p] = src[p], src[9+p]
+			}
+			if !slices.Equal(dst, want) {
+				t.Errorf("filled row %v, want %v", dst, want)
+			}
+
+			fillBox(src, nil, shape, tt.top, tt.left, tt.h, tt.w)
+			for i := range src {
+				if zeroed := want[i] != 0; zeroed != (src[i] == 0) {
+					t.Fatalf("zeroing the box left %v", src)
+				}
+			}
+		})
+	}
+}
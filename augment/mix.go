@@ -0,0 +1,94 @@
+package augment
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func sampleBeta(alpha float64, rng *rand.Rand) float64 {
+	return distuv.Beta{Alpha: alpha, Beta: alpha, Src: rng}.Rand()
+}
+
+// Mixup blends every sample with another sample of the batch,
+// x = lambda*x + (1-lambda)*x', and blends the targets and sample weights the
+// same way, so the loss sees soft targets. lambda is drawn from
+// Beta(Alpha, Alpha) per batch. Targets must be one-hot or soft rows, not
+// label columns.
+type Mixup struct {
+	Alpha float64
+}
+
+func NewMixup(alpha float64) *Mixup {
+	return &Mixup{Alpha: alpha}
+}
+
+func (a *Mixup) Augment(x, y *mat.Dense, w []float64, rng *rand.Rand) {
+	r, _ := x.Dims()
+	lambda := sampleBeta(a.Alpha, rng)
+	perm := rng.Perm(r)
+
+	srcX, srcY := mat.DenseCopyOf(x), mat.DenseCopyOf(y)
+	for i := 0; i < r; i++ {
+		blend(x.RawRowView(i), srcX.RawRowView(perm[i]), lambda)
+		blend(y.RawRowView(i), srcY.RawRowView(perm[i]), lambda)
+	}
+	blendWeights(w, perm, lambda)
+}
+
+// CutMix pastes a random box from another sample of the batch into every
+// image and mixes the targets and sample weights in proportion to the pasted
+// area. The box covers a fraction 1-lambda of the image with lambda drawn
+// from Beta(Alpha, Alpha) per batch. Targets must be one-hot or soft rows.
+type CutMix struct {
+	Shape Shape
+	Alpha float64
+}
+
+func NewCutMix(shape Shape, alpha float64) *CutMix {
+	return &CutMix{Shape: shape, Alpha: alpha}
+}
+
+func (a *CutMix) Augment(x, y *mat.Dense, weights []float64, rng *rand.Rand) {
+	r, _ := x.Dims()
+	lambda := sampleBeta(a.Alpha, rng)
+	perm := rng.Perm(r)
+
+	ratio := math.Sqrt(1 - lambda)
+	h := int(ratio * float64(a.Shape.Rows))
+	w := int(ratio * float64(a.Shape.Cols))
+	total := float64(a.Shape.channelSize())
+
+	srcX, srcY := mat.DenseCopyOf(x), mat.DenseCopyOf(y)
+	srcW := append([]float64(nil), weights...)
+	for i := 0; i < r; i++ {
+		cy, cx := rng.IntN(a.Shape.Rows), rng.IntN(a.Shape.Cols)
+		covered := fillBox(x.RawRowView(i), srcX.RawRowView(perm[i]), a.Shape, cy-h/2, cx-w/2, h, w)
+		// the box may be clipped, so mix targets by the area actually pasted
+		mix := 1 - float64(covered)/total
+		blend(y.RawRowView(i), srcY.RawRowView(perm[i]), mix)
+		if weights != nil {
+			weights[i] = mix*srcW[i] + (1-mix)*srcW[perm[i]]
+		}
+	}
+}
+
+// blendWeights mixes the sample weights w, which may be nil, like the samples
+// they belong to.
+func blendWeights(w []float64, perm []int, lambda float64) {
+	if w == nil {
+		return
+	}
+	src := append([]float64(nil), w...)
+	for i := range w {
+		w[i] = lambda*src[i] + (1-lambda)*src[perm[i]]
+	}
+}
+
+func blend(dst, src []float64, lambda float64) {
+	for j := range dst {
+		dst[j] = lambda*dst[j] + (1-lambda)*src[j]
+	}
+}
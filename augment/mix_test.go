@@ -0,0 +1,70 @@
+package augment
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// With identity targets, y row i holds the share of every original sample in
+// mixed sample i, so the mixed weights must be the same combination of the
+// original weights.
+func TestMixingBlendsWeightsLikeTargets(t *testing.T) {
+	const n = 6
+	shape := Shape{Channels: 1, Rows: 4, Cols: 4}
+	steps := map[string]Augmentation{
+		"mixup":  NewMixup(0.4),
+		"cutmix": NewCutMix(shape, 1),
+	}
+
+	for name, step := range steps {
+		t.Run(name, func(t *testing.T) {
+			x := mat.NewDense(n, shape.channelSize(), nil)
+			y := mat.NewDense(n, n, nil)
+			w := make([]float64, n)
+			for i := 0; i < n; i++ {
+				y.Set(i, i, 1)
+				w[i] = float64(i + 1)
+			}
+
+			p := NewPipeline(7, step)
+			_, outY, outW := p.Apply(x, y, w)
+
+			for i := 0; i < n; i++ {
+				want, share := 0.0, 0.0
+				for j := 0; j < n; j++ {
+					want += outY.At(i, j) * w[j]
+					share += outY.At(i, j)
+				}
+				if math.Abs(share-1) > 1e-12 {
+					t.Errorf("target row %d sums to %v, want 1", i, share)
+				}
+				if math.Abs(outW[i]-want) > 1e-12 {
+					t.Errorf("weight %d = %v, want %v", i, outW[i], want)
+				}
+			}
+			if w[0] != 1 || y.At(0, 0) != 1 {
+				t.Error("Apply modified its inputs")
+			}
+		})
+	}
+}
+
+func TestPipelineIsSeedable(t *testing.T) {
+	x := mat.NewDense(3, 16, nil)
+	for i := range x.RawMatrix().Data {
+		x.RawMatrix().Data[i] = float64(i)
+	}
+	y := mat.NewDense(3, 1, nil)
+	shape := Shape{Channels: 1, Rows: 4, Cols: 4}
+
+	run := func() *mat.Dense {
+		p := NewPipeline(42, NewRandomTranslation(shape, 1), NewGaussianNoise(0.1))
+		out, _, _ := p.Apply(x, y, nil)
+		return out
+	}
+	if !mat.Equal(run(), run()) {
+		t.Error("pipelines with the same seed produced different batches")
+	}
+}
@@ -0,0 +1,102 @@
+package augment
+
+import (
+	"math/rand/v2"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ColorJitter adds a random brightness offset in [-Brightness, Brightness]
+// and scales contrast around the image mean by a factor in
+// [1-Contrast, 1+Contrast], independently for every image.
+type ColorJitter struct {
+	Brightness float64
+	Contrast   float64
+}
+
+func NewColorJitter(brightness, contrast float64) *ColorJitter {
+	return &ColorJitter{Brightness: brightness, Contrast: contrast}
+}
+
+func (a *ColorJitter) Augment(x, _ *mat.Dense, _ []float64, rng *rand.Rand) {
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		row := x.RawRowView(i)
+		offset := (rng.Float64()*2 - 1) * a.Brightness
+		factor := 1 + (rng.Float64()*2-1)*a.Contrast
+
+		mean := 0.0
+		for _, v := range row {
+			mean += v
+		}
+		mean /= float64(len(row))
+
+		for j, v := range row {
+			row[j] = (v-mean)*factor + mean + offset
+		}
+	}
+}
+
+// GaussianNoise adds zero-mean noise with standard deviation Std to every value.
+type GaussianNoise struct {
+	Std float64
+}
+
+func NewGaussianNoise(std float64) *GaussianNoise {
+	return &GaussianNoise{Std: std}
+}
+
+func (a *GaussianNoise) Augment(x, _ *mat.Dense, _ []float64, rng *rand.Rand) {
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		row := x.RawRowView(i)
+		for j := range row {
+			row[j] += rng.NormFloat64() * a.Std
+		}
+	}
+}
+
+// Cutout zeroes a Size x Size square at a random position in every image,
+// across all channels. The square is clipped at the image border.
+type Cutout struct {
+	Shape Shape
+	Size  int
+}
+
+func NewCutout(shape Shape, size int) *Cutout {
+	return &Cutout{Shape: shape, Size: size}
+}
+
+func (a *Cutout) Augment(x, _ *mat.Dense, _ []float64, rng *rand.Rand) {
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		cy, cx := rng.IntN(a.Shape.Rows), rng.IntN(a.Shape.Cols)
+		fillBox(x.RawRowView(i), nil, a.Shape, cy-a.Size/2, cx-a.Size/2, a.Size, a.Size)
+	}
+}
+
+// fillBox copies the h x w box at (top, left) from src into dst, or zeroes it
+// when src is nil. The box is clipped at the image border and the number of
+// pixels covered is returned.
+func fillBox(dst, src []float64, shape Shape, top, left, h, w int) int {
+	y0, y1 := max(top, 0), min(top+h, shape.Rows)
+	x0, x1 := max(left, 0), min(left+w, shape.Cols)
+	if y0 >= y1 || x0 >= x1 {
+		return 0
+	}
+
+	size := shape.channelSize()
+	for c := 0; c < shape.Channels; c++ {
+		for y := y0; y < y1; y++ {
+			for xx := x0; xx < x1; xx++ {
+				idx := c*size + y*shape.Cols + xx
+				if src == nil {
+					dst[idx] = 0
+				} else {
+					dst[idx] = src[idx]
+				}
+			}
+		}
+	}
+	return (y1 - y0) * (x1 - x0)
+}
@@ -0,0 +1,115 @@
+package augment
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestColorJitter(t *testing.T) {
+	shape := Shape{Channels: 1, Rows: 3, Cols: 3}
+	x := ramp(10, shape)
+
+	t.Run("brightness", func(t *testing.T) {
+		out, _, _ := NewPipeline(1, NewColorJitter(0.5, 0)).Apply(x, x, nil)
+		for i := 0; i < 10; i++ {
+			orig, got := x.RawRowView(i), out.RawRowView(i)
+			offset := got[0] - orig[0]
+			if math.Abs(offset) > 0.5 {
+				t.Errorf("image %d: offset %v exceeds 0.5", i, offset)
+			}
+			for j := range got {
+				if math.Abs(got[j]-orig[j]-offset) > 1e-12 {
+					t.Fatalf("image %d: pixels shifted unevenly: %v", i, got)
+				}
+			}
+		}
+	})
+
+	t.Run("contrast", func(t *testing.T) {
+		out, _, _ := NewPipeline(1, NewColorJitter(0, 0.3)).Apply(x, x, nil)
+		for i := 0; i < 10; i++ {
+			orig, got := x.RawRowView(i), out.RawRowView(i)
+			mean := stat.Mean(orig, nil)
+			if math.Abs(stat.Mean(got, nil)-mean) > 1e-9 {
+				t.Errorf("image %d: mean moved from %v to %v", i, mean, stat.Mean(got, nil))
+			}
+			factor := (got[0] - mean) / (orig[0] - mean)
+			if factor < 0.7 || factor > 1.3 {
+				t.Errorf("image %d: contrast factor %v outside [0.7, 1.3]", i, factor)
+			}
+			for j := range got {
+				if math.Abs(got[j]-mean-factor*(orig[j]-mean)) > 1e-9 {
+					t.Fatalf("image %d: deviations scaled unevenly: %v", i, got)
+				}
+			}
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		out, _, _ := NewPipeline(1, NewColorJitter(0, 0)).Apply(x, x, nil)
+		if !mat.EqualApprox(out, x, 1e-12) {
+			t.Errorf("jitter without range changed the batch: %v", mat.Formatted(out))
+		}
+	})
+}
+
+func TestGaussianNoise(t *testing.T) {
+	x := mat.NewDense(4, 5000, nil)
+	out, _, _ := NewPipeline(1, NewGaussianNoise(0.5)).Apply(x, x, nil)
+
+	noise := out.RawMatrix().Data
+	if mean := stat.Mean(noise, nil); math.Abs(mean) > 0.02 {
+		t.Errorf("noise mean = %v, want about 0", mean)
+	}
+	if std := stat.StdDev(noise, nil); math.Abs(std-0.5) > 0.02 {
+		t.Errorf("noise std = %v, want about 0.5", std)
+	}
+
+	if out, _, _ := NewPipeline(1, NewGaussianNoise(0)).Apply(x, x, nil); !mat.Equal(out, x) {
+		t.Error("noise with zero std changed the batch")
+	}
+}
+
+// Cutout zeroes one box, clipped at the border, at the same place in every
+// channel.
+func TestCutout(t *testing.T) {
+	shape := Shape{Channels: 2, Rows: 5, Cols: 5}
+	x := ramp(30, shape)
+	out, _, _ := NewPipeline(1, NewCutout(shape, 3)).Apply(x, x, nil)
+
+	size := shape.channelSize()
+	clipped := false
+	for i := 0; i < 30; i++ {
+		orig, got := x.RawRowView(i), out.RawRowView(i)
+		top, left, bottom, right := shape.Rows, shape.Cols, -1, -1
+		zeroed := 0
+		for p := 0; p < size; p++ {
+			first, second := got[p] == 0, got[size+p] == 0
+			if first != second {
+				t.Fatalf("image %d: pixel %d is zeroed in one channel only", i, p)
+			}
+			if !first {
+				if got[p] != orig[p] || got[size+p] != orig[size+p] {
+					t.Fatalf("image %d: pixel %d changed outside the box", i, p)
+				}
+				continue
+			}
+			zeroed++
+			y, xx := p/shape.Cols, p%shape.Cols
+			top, left = min(top, y), min(left, xx)
+			bottom, right = max(bottom, y), max(right, xx)
+		}
+
+		h, w := bottom-top+1, right-left+1
+		if zeroed == 0 || zeroed != h*w || h > 3 || w > 3 {
+			t.Fatalf("image %d: %d zeros do not form a box of at most 3x3", i, zeroed)
+		}
+		clipped = clipped || h < 3 || w < 3
+	}
+	if !clipped {
+		t.Error("no box centred near the border was clipped")
+	}
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	cifarChannels = 3
+	cifarSide     = 32
+	cifarPixels   = cifarChannels * cifarSide * cifarSide
+	cifarClasses  = 10
+)
+
+// LoadCIFAR10 reads CIFAR-10 binary batch files. Every record is a label byte
+// followed by 3x32x32 channel-major pixels, which map directly onto a row of
+// X with InChannels 3 as expected by layer.Conv. Pixels are scaled to [0, 1];
+// use ChannelStats and NormalizeChannels for per-channel standardization.
+func LoadCIFAR10(paths ...string) (X, Y *mat.Dense, err error) {
+	var xData, yData []float64
+	for _, path := range paths {
+		xData, yData, err = readCIFARBatch(path, xData, yData)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	n := len(yData) / cifarClasses
+	if n == 0 {
+		return nil, nil, errors.New("no CIFAR-10 records found")
+	}
+	return mat.NewDense(n, cifarPixels, xData), mat.NewDense(n, cifarClasses, yData), nil
+}
+
+func readCIFARBatch(path string, xData, yData []float64) ([]float64, []float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	record := make([]byte, 1+cifarPixels)
+	for i := 0; ; i++ {
+		_, err := io.ReadFull(r, record)
+		if err == io.EOF {
+			return xData, yData, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: record %d: %w", path, i, err)
+		}
+
+		label := int(record[0])
+		if label >= cifarClasses {
+			return nil, nil, fmt.Errorf("%s: record %d: label %d out of range", path, i, label)
+		}
+		oneHot := make([]float64, cifarClasses)
+		oneHot[label] = 1
+		yData = append(yData, oneHot...)
+
+		for _, p := range record[1:] {
+			xData = append(xData, float64(p)/255)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"bytes"
+	"math"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// cifarRecord encodes one CIFAR-10 record whose channel ch is filled with
+// pixel[ch].
+func cifarRecord(label byte, pixel [cifarChannels]byte) []byte {
+	record := []byte{label}
+	for _, p := range pixel {
+		record = append(record, bytes.Repeat([]byte{p}, cifarSide*cifarSide)...)
+	}
+	return record
+}
+
+func TestLoadCIFAR10(t *testing.T) {
+	first := writeFixture(t, "data_batch_1.bin", append(
+		cifarRecord(3, [3]byte{0, 51, 255}),
+		cifarRecord(9, [3]byte{255, 0, 102})...,
+	))
+	second := writeFixture(t, "data_batch_2.bin", cifarRecord(0, [3]byte{153, 153, 153}))
+
+	X, Y, err := LoadCIFAR10(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, c := X.Dims(); r != 3 || c != cifarPixels {
+		t.Fatalf("X is %dx%d, want 3x%d", r, c, cifarPixels)
+	}
+
+	wantPixels := [][3]float64{{0, 0.2, 1}, {1, 0, 0.4}, {0.6, 0.6, 0.6}}
+	channelSize := cifarSide * cifarSide
+	for i, want := range wantPixels {
+		for ch, v := range want {
+			// first and last pixel of every channel
+			for _, j := range []int{ch * channelSize, (ch+1)*channelSize - 1} {
+				if got := X.At(i, j); math.Abs(got-v) > 1e-12 {
+					t.Errorf("sample %d pixel %d = %v, want %v", i, j, got, v)
+				}
+			}
+		}
+	}
+
+	for i, label := range []int{3, 9, 0} {
+		want := make([]float64, cifarClasses)
+		want[label] = 1
+		if got := Y.RawRowView(i); !slices.Equal(got, want) {
+			t.Errorf("sample %d label row %v, want one-hot %d", i, got, label)
+		}
+	}
+}
+
+func TestLoadCIFAR10Errors(t *testing.T) {
+	record := cifarRecord(1, [3]byte{})
+	tests := map[string][]byte{
+		"empty":       nil,
+		"truncated":   append(record, record[:100]...),
+		"label range": cifarRecord(10, [3]byte{}),
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := LoadCIFAR10(writeFixture(t, "batch.bin", content)); err == nil {
+				t.Error("LoadCIFAR10 succeeded")
+			}
+		})
+	}
+}
+
+func TestNormalizeChannels(t *testing.T) {
+	// two channels of two values each
+	X := mat.NewDense(2, 4, []float64{
+		1, 3, 5, 5,
+		1, 3, 5, 5,
+	})
+	mean, std := ChannelStats(X, 2)
+	if mean[0] != 2 || mean[1] != 5 || std[0] != 1 || std[1] != 0 {
+		t.Fatalf("stats mean %v std %v, want [2 5] [1 0]", mean, std)
+	}
+
+	NormalizeChannels(X, mean, std)
+	// a constant channel is centered but not scaled
+	want := mat.NewDense(2, 4, []float64{
+		-1, 1, 0, 0,
+		-1, 1, 0, 0,
+	})
+	if !mat.EqualApprox(X, want, 1e-12) {
+		t.Errorf("normalized\n%v\nwant\n%v", mat.Formatted(X), mat.Formatted(want))
+	}
+}
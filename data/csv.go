@@ -0,0 +1,409 @@
+package data
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Imputation selects how missing numeric values are filled in. Categorical
+// columns are imputed with their most frequent level unless the imputation is
+// ImputeNone.
+type Imputation int
+
+const (
+	// ImputeNone reports a missing value as an error.
+	ImputeNone Imputation = iota
+	// ImputeZero fills numeric columns with 0.
+	ImputeZero
+	// ImputeMean fills numeric columns with the column mean.
+	ImputeMean
+	// ImputeMedian fills numeric columns with the column median.
+	ImputeMedian
+)
+
+type CSVConfig struct {
+	Comma       rune
+	Header      bool
+	Features    []string
+	Targets     []string
+	Categorical []string
+	Levels      map[string][]string
+	Missing     []string
+	Impute      Imputation
+}
+
+type CSVOption func(*CSVConfig)
+
+// WithComma sets the field delimiter. The default is ','.
+func WithComma(r rune) CSVOption {
+	return func(c *CSVConfig) {
+		c.Comma = r
+	}
+}
+
+// WithHeader controls whether the first record holds column names. Without a
+// header, columns are named by their zero-based index: "0", "1", ...
+func WithHeader(header bool) CSVOption {
+	return func(c *CSVConfig) {
+		c.Header = header
+	}
+}
+
+// WithFeatures selects the feature columns. By default every column that is
+// not a target is a feature.
+func WithFeatures(columns ...string) CSVOption {
+	return func(c *CSVConfig) {
+		c.Features = columns
+	}
+}
+
+// WithTargets selects the target columns. By default the last column is the
+// target.
+func WithTargets(columns ...string) CSVOption {
+	return func(c *CSVConfig) {
+		c.Targets = columns
+	}
+}
+
+// WithCategorical one-hot encodes the given columns.
+func WithCategorical(columns ...string) CSVOption {
+	return func(c *CSVConfig) {
+		c.Categorical = append(c.Categorical, columns...)
+	}
+}
+
+// WithLevels fixes the categories of a categorical column and their order,
+// so that separately loaded files share one encoding. Without it the levels
+// are the sorted distinct values found in the file.
+func WithLevels(column string, levels []string) CSVOption {
+	return func(c *CSVConfig) {
+		if c.Levels == nil {
+			c.Levels = make(map[string][]string)
+		}
+		c.Levels[column] = levels
+	}
+}
+
+// WithMissing sets the tokens treated as missing values. The default is the
+// empty string, "NA", "N/A", "NaN" and "null".
+func WithMissing(tokens ...string) CSVOption {
+	return func(c *CSVConfig) {
+		c.Missing = tokens
+	}
+}
+
+func WithImputation(imp Imputation) CSVOption {
+	return func(c *CSVConfig) {
+		c.Impute = imp
+	}
+}
+
+// CSVError reports a problem with a single cell. Row is the 1-based record
+// number in the file, counting the header.
+type CSVError struct {
+	Row    int
+	Column string
+	Err    error
+}
+
+func (e *CSVError) Error() string {
+	return fmt.Sprintf("csv row %d, column %q: %v", e.Row, e.Column, e.Err)
+}
+
+func (e *CSVError) Unwrap() error {
+	return e.Err
+}
+
+var ErrMissingValue = errors.New("missing value")
+
+// Table is a CSV file converted to matrices. Categorical columns expand to
+// one column per level, named "column=level".
+type Table struct {
+	X, Y         *mat.Dense
+	FeatureNames []string
+	TargetNames  []string
+	// Levels holds the categories of every categorical column in encoding
+	// order; pass them to WithLevels when loading matching files.
+	Levels map[string][]string
+}
+
+// column is a selected CSV column after parsing.
+type column struct {
+	name        string
+	categorical bool
+	numbers     []float64
+	strings     []string
+	missing     []bool
+	levels      []string
+}
+
+// LoadCSV parses r into feature and target matrices.
+func LoadCSV(r io.Reader, opts ...CSVOption) (*Table, error) {
+	conf := &CSVConfig{
+		Comma:   ',',
+		Header:  true,
+		Missing: []string{"", "NA", "N/A", "NaN", "null"},
+	}
+	for _, opt := range opts {
+		opt(conf)
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = conf.Comma
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv is empty")
+	}
+
+	var names []string
+	firstRow := 1
+	if conf.Header {
+		names = records[0]
+		records = records[1:]
+		firstRow = 2
+	} else {
+		for i := range records[0] {
+			names = append(names, strconv.Itoa(i))
+		}
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv has no data rows")
+	}
+
+	indexOf := make(map[string]int, len(names))
+	for i, name := range names {
+		indexOf[strings.TrimSpace(name)] = i
+	}
+
+	targets := conf.Targets
+	if len(targets) == 0 {
+		targets = []string{strings.TrimSpace(names[len(names)-1])}
+	}
+	features := conf.Features
+	if len(features) == 0 {
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if !slices.Contains(targets, name) {
+				features = append(features, name)
+			}
+		}
+	}
+
+	parse := func(names []string) ([]*column, error) {
+		cols := make([]*column, len(names))
+		for i, name := range names {
+			idx, ok := indexOf[name]
+			if !ok {
+				return nil, fmt.Errorf("csv has no column %q", name)
+			}
+			col, err := parseColumn(records, idx, name, firstRow, conf)
+			if err != nil {
+				return nil, err
+			}
+			cols[i] = col
+		}
+		return cols, nil
+	}
+
+	featureCols, err := parse(features)
+	if err != nil {
+		return nil, err
+	}
+	targetCols, err := parse(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{Levels: make(map[string][]string)}
+	table.X, table.FeatureNames, err = encodeColumns(featureCols, len(records), "feature")
+	if err != nil {
+		return nil, err
+	}
+	table.Y, table.TargetNames, err = encodeColumns(targetCols, len(records), "target")
+	if err != nil {
+		return nil, err
+	}
+	for _, col := range append(featureCols, targetCols...) {
+		if col.categorical {
+			table.Levels[col.name] = col.levels
+		}
+	}
+	return table, nil
+}
+
+// LoadCSVFile is LoadCSV for a file on disk.
+func LoadCSVFile(path string, opts ...CSVOption) (*Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	table, err := LoadCSV(file, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return table, nil
+}
+
+func parseColumn(records [][]string, idx int, name string, firstRow int, conf *CSVConfig) (*column, error) {
+	col := &column{
+		name:        name,
+		categorical: slices.Contains(conf.Categorical, name),
+		missing:     make([]bool, len(records)),
+	}
+	if col.categorical {
+		col.strings = make([]string, len(records))
+	} else {
+		col.numbers = make([]float64, len(records))
+	}
+
+	for i, record := range records {
+		value := strings.TrimSpace(record[idx])
+		if slices.Contains(conf.Missing, value) {
+			if conf.Impute == ImputeNone {
+				return nil, &CSVError{Row: firstRow + i, Column: name, Err: ErrMissingValue}
+			}
+			col.missing[i] = true
+			continue
+		}
+
+		if col.categorical {
+			col.strings[i] = value
+			continue
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, &CSVError{Row: firstRow + i, Column: name, Err: err}
+		}
+		col.numbers[i] = v
+	}
+
+	if col.categorical {
+		return col, col.imputeCategorical(conf.Levels[name], firstRow)
+	}
+	col.imputeNumeric(conf.Impute)
+	return col, nil
+}
+
+func (col *column) imputeNumeric(imp Imputation) {
+	var present []float64
+	for i, v := range col.numbers {
+		if !col.missing[i] {
+			present = append(present, v)
+		}
+	}
+
+	fill := 0.0
+	switch {
+	case len(present) == 0:
+	case imp == ImputeMean:
+		for _, v := range present {
+			fill += v
+		}
+		fill /= float64(len(present))
+	case imp == ImputeMedian:
+		sort.Float64s(present)
+		mid := len(present) / 2
+		if len(present)%2 == 0 {
+			fill = (present[mid-1] + present[mid]) / 2
+		} else {
+			fill = present[mid]
+		}
+	}
+
+	for i := range col.numbers {
+		if col.missing[i] {
+			col.numbers[i] = fill
+		}
+	}
+}
+
+func (col *column) imputeCategorical(levels []string, firstRow int) error {
+	counts := make(map[string]int)
+	for i, v := range col.strings {
+		if !col.missing[i] {
+			counts[v]++
+		}
+	}
+
+	if levels == nil {
+		for v := range counts {
+			levels = append(levels, v)
+		}
+		sort.Strings(levels)
+	}
+	col.levels = levels
+
+	mostFrequent, best := "", -1
+	for _, level := range levels {
+		if counts[level] > best {
+			mostFrequent, best = level, counts[level]
+		}
+	}
+
+	for i, v := range col.strings {
+		if col.missing[i] {
+			if len(levels) == 0 {
+				return &CSVError{Row: firstRow + i, Column: col.name, Err: errors.New("no category to impute the missing value with")}
+			}
+			col.strings[i] = mostFrequent
+			continue
+		}
+		if !slices.Contains(levels, v) {
+			return &CSVError{Row: firstRow + i, Column: col.name, Err: fmt.Errorf("unknown category %q", v)}
+		}
+	}
+	return nil
+}
+
+// encodeColumns lays out cols as a matrix, one-hot encoding categorical ones.
+// kind names the columns in the error returned when there is nothing to
+// encode.
+func encodeColumns(cols []*column, rows int, kind string) (*mat.Dense, []string, error) {
+	var names []string
+	for _, col := range cols {
+		if col.categorical {
+			for _, level := range col.levels {
+				names = append(names, col.name+"="+level)
+			}
+		} else {
+			names = append(names, col.name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("csv has no %s columns", kind)
+	}
+
+	m := mat.NewDense(rows, len(names), nil)
+	offset := 0
+	for _, col := range cols {
+		if !col.categorical {
+			for i, v := range col.numbers {
+				m.Set(i, offset, v)
+			}
+			offset++
+			continue
+		}
+
+		for i, v := range col.strings {
+			m.Set(i, offset+slices.Index(col.levels, v), 1)
+		}
+		offset += len(col.levels)
+	}
+	return m, names, nil
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLoadCSV(t *testing.T) {
+	const input = `age,city,income,label
+30,kyiv,100,1
+NA,lviv,,0
+50,kyiv,300,1
+`
+	tests := []struct {
+		name         string
+		opts         []CSVOption
+		wantX        []float64
+		wantFeatures []string
+	}{
+		{
+			name:         "mean imputation",
+			opts:         []CSVOption{WithCategorical("city"), WithImputation(ImputeMean)},
+			wantX:        []float64{30, 1, 0, 100, 40, 0, 1, 200, 50, 1, 0, 300},
+			wantFeatures: []string{"age", "city=kyiv", "city=lviv", "income"},
+		},
+		{
+			name:         "zero imputation and column selection",
+			opts:         []CSVOption{WithFeatures("income", "age"), WithImputation(ImputeZero)},
+			wantX:        []float64{100, 30, 0, 0, 300, 50},
+			wantFeatures: []string{"income", "age"},
+		},
+		{
+			name:         "fixed levels",
+			opts:         []CSVOption{WithFeatures("city"), WithCategorical("city"), WithLevels("city", []string{"lviv", "kyiv", "odesa"})},
+			wantX:        []float64{0, 1, 0, 1, 0, 0, 0, 1, 0},
+			wantFeatures: []string{"city=lviv", "city=kyiv", "city=odesa"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := LoadCSV(strings.NewReader(input), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := mat.NewDense(3, len(tt.wantFeatures), tt.wantX)
+			if !mat.Equal(table.X, want) {
+				t.Errorf("X =\n%v\nwant\n%v", mat.Formatted(table.X), mat.Formatted(want))
+			}
+			if strings.Join(table.FeatureNames, ",") != strings.Join(tt.wantFeatures, ",") {
+				t.Errorf("features %v, want %v", table.FeatureNames, tt.wantFeatures)
+			}
+			if !mat.Equal(table.Y, mat.NewDense(3, 1, []float64{1, 0, 1})) {
+				t.Errorf("Y = %v, want [1 0 1]", table.Y.RawMatrix().Data)
+			}
+		})
+	}
+}
+
+func TestLoadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    []CSVOption
+		wantRow int
+		wantCol string
+		want    string
+	}{
+		{"missing without imputation", "a,b\n1,2\n,3\n", nil, 3, "a", "missing value"},
+		{"not a number", "a,b\n1,2\nx,3\n", nil, 3, "a", "invalid syntax"},
+		{"unknown category", "c,b\nx,1\ny,2\n", []CSVOption{WithCategorical("c"), WithLevels("c", []string{"x"})}, 3, "c", "unknown category"},
+		{"all categories missing", "c,b\nNA,1\nNA,2\n", []CSVOption{WithCategorical("c"), WithImputation(ImputeZero)}, 2, "c", "no category"},
+		{"empty levels", "c,b\nNA,1\n", []CSVOption{WithCategorical("c"), WithLevels("c", []string{}), WithImputation(ImputeZero)}, 2, "c", "no category"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCSV(strings.NewReader(tt.input), tt.opts...)
+			var csvErr *CSVError
+			if !errors.As(err, &csvErr) {
+				t.Fatalf("got error %v, want a CSVError", err)
+			}
+			if csvErr.Row != tt.wantRow || csvErr.Column != tt.wantCol || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want row %d column %q with %q", err, tt.wantRow, tt.wantCol, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCSVEmptyColumnSets(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []CSVOption
+	}{
+		{"single column", "y\n1\n2\n", nil},
+		{"every column a target", "a,b\n1,2\n", []CSVOption{WithTargets("a", "b")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCSV(strings.NewReader(tt.input), tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), "no feature columns") {
+				t.Fatalf("got error %v, want no feature columns", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Package data provides datasets that can be streamed to a network batch by
+// batch instead of being held in two dense matrices.
+package data
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Dataset is a random-access collection of samples. Every sample is an input
+// row of length inputs and a target row of length outputs, as in the X and Y
+// matrices accepted by Fit.
+type Dataset interface {
+	Len() int
+	Dims() (inputs, outputs int)
+	// Sample copies sample i into x and y, which have the lengths reported
+	// by Dims.
+	Sample(i int, x, y []float64) error
+}
+
+// InMemory serves samples from matrices that are already loaded.
+type InMemory struct {
+	X, Y *mat.Dense
+}
+
+func NewInMemory(X, Y *mat.Dense) *InMemory {
+	return &InMemory{X: X, Y: Y}
+}
+
+func (d *InMemory) Len() int {
+	r, _ := d.X.Dims()
+	return r
+}
+
+func (d *InMemory) Dims() (int, int) {
+	_, inputs := d.X.Dims()
+	_, outputs := d.Y.Dims()
+	return inputs, outputs
+}
+
+func (d *InMemory) Sample(i int, x, y []float64) error {
+	if i < 0 || i >= d.Len() {
+		return fmt.Errorf("sample %d out of range [0, %d)", i, d.Len())
+	}
+	copy(x, d.X.RawRowView(i))
+	copy(y, d.Y.RawRowView(i))
+	return nil
+}
+
+// Load reads every sample of ds into memory.
+func Load(ds Dataset) (X, Y *mat.Dense, err error) {
+	n := ds.Len()
+	inputs, outputs := ds.Dims()
+	X = mat.NewDense(n, inputs, nil)
+	Y = mat.NewDense(n, outputs, nil)
+	for i := 0; i < n; i++ {
+		if err := ds.Sample(i, X.RawRowView(i), Y.RawRowView(i)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return X, Y, nil
+}
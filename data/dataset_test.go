@@ -0,0 +1,171 @@
+package data
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func writeFixture(t *testing.T, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func sequentialDataset(n int) *InMemory {
+	X := mat.NewDense(n, 2, nil)
+	Y := mat.NewDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		X.SetRow(i, []float64{float64(i), -float64(i) / 4})
+		Y.Set(i, 0, float64(i%3))
+	}
+	return NewInMemory(X, Y)
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	src := sequentialDataset(5)
+	path := filepath.Join(t.TempDir(), "samples.nnsd")
+	if err := WriteFile(path, src); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if inputs, outputs := f.Dims(); f.Len() != 5 || inputs != 2 || outputs != 1 {
+		t.Fatalf("file holds %d samples of %d -> %d values, want 5 of 2 -> 1", f.Len(), inputs, outputs)
+	}
+	X, Y, err := Load(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the values are exactly representable as float32
+	if !mat.Equal(X, src.X) || !mat.Equal(Y, src.Y) {
+		t.Errorf("round trip changed the samples:\n%v\n%v", mat.Formatted(X), mat.Formatted(Y))
+	}
+
+	x, y := make([]float64, 2), make([]float64, 1)
+	for _, i := range []int{-1, 5} {
+		if err := f.Sample(i, x, y); err == nil {
+			t.Errorf("Sample(%d) succeeded on 5 samples", i)
+		}
+	}
+}
+
+func TestOpenFileRejectsForeignFiles(t *testing.T) {
+	tests := map[string][]byte{
+		"short":     []byte("NNSD"),
+		"bad magic": make([]byte, fileHeaderSize),
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if f, err := OpenFile(writeFixture(t, "dataset", content)); err == nil {
+				f.Close()
+				t.Error("OpenFile succeeded")
+			}
+		})
+	}
+}
+
+// collect drains it and returns the first input value of every sample along
+// with the batch sizes.
+func collect(t *testing.T, it Iterator) (firsts []float64, sizes []int) {
+	t.Helper()
+	defer it.Close()
+	for it.Next() {
+		x, y := it.Batch()
+		r, _ := x.Dims()
+		if yr, _ := y.Dims(); yr != r {
+			t.Fatalf("batch has %d inputs but %d targets", r, yr)
+		}
+		sizes = append(sizes, r)
+		for i := 0; i < r; i++ {
+			firsts = append(firsts, x.At(i, 0))
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return firsts, sizes
+}
+
+func TestIterator(t *testing.T) {
+	ds := sequentialDataset(7)
+	ordered := []float64{0, 1, 2, 3, 4, 5, 6}
+
+	firsts, sizes := collect(t, NewIterator(ds, 3))
+	if !slices.Equal(firsts, ordered) || !slices.Equal(sizes, []int{3, 3, 1}) {
+		t.Errorf("got samples %v in batches %v", firsts, sizes)
+	}
+
+	shuffled, _ := collect(t, NewIterator(ds, 3, WithShuffle(42)))
+	again, _ := collect(t, NewIterator(ds, 3, WithShuffle(42)))
+	if !slices.Equal(shuffled, again) {
+		t.Errorf("same seed gave orders %v and %v", shuffled, again)
+	}
+	if slices.Equal(shuffled, ordered) {
+		t.Error("shuffled iterator kept the original order")
+	}
+	slices.Sort(shuffled)
+	if !slices.Equal(shuffled, ordered) {
+		t.Errorf("shuffled iterator visited %v", shuffled)
+	}
+}
+
+type failingDataset struct {
+	*InMemory
+	at int
+}
+
+var errSample = errors.New("broken sample")
+
+func (d failingDataset) Sample(i int, x, y []float64) error {
+	if i == d.at {
+		return errSample
+	}
+	return d.InMemory.Sample(i, x, y)
+}
+
+func TestIteratorStopsOnError(t *testing.T) {
+	it := NewIterator(failingDataset{sequentialDataset(6), 4}, 2)
+	defer it.Close()
+
+	batches := 0
+	for it.Next() {
+		batches++
+	}
+	if batches != 2 || !errors.Is(it.Err(), errSample) {
+		t.Errorf("got %d batches and error %v, want 2 and %v", batches, it.Err(), errSample)
+	}
+}
+
+// Closing an iterator early must not leave its producer blocked.
+func TestIteratorCloseEarly(t *testing.T) {
+	it := NewIterator(sequentialDataset(100), 1)
+	it.Next()
+	it.Close()
+	it.Close()
+}
+
+func TestIteratorBatchSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for batch size %d", size)
+				}
+			}()
+			NewIterator(sequentialDataset(3), size)
+		}()
+	}
+}
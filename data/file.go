@@ -0,0 +1,132 @@
+package data
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+var fileMagic = [4]byte{'N', 'N', 'S', 'D'}
+
+const fileHeaderSize = 4 + 3*8
+
+// File is a dataset stored on disk and read one sample at a time. Values are
+// kept as little-endian float32, inputs followed by targets for every sample.
+// It is safe for concurrent use.
+type File struct {
+	f               *os.File
+	n               int
+	inputs, outputs int
+	recordSize      int64
+}
+
+// WriteFile stores every sample of ds at path in the format read by OpenFile.
+func WriteFile(path string, ds Dataset) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	// on success the explicit Close below reports the final error
+	defer func() {
+		if err != nil {
+			file.Close()
+		}
+	}()
+
+	w := bufio.NewWriter(file)
+	inputs, outputs := ds.Dims()
+
+	if _, err := w.Write(fileMagic[:]); err != nil {
+		return err
+	}
+	for _, v := range []int{ds.Len(), inputs, outputs} {
+		if err := binary.Write(w, binary.LittleEndian, uint64(v)); err != nil {
+			return err
+		}
+	}
+
+	x := make([]float64, inputs)
+	y := make([]float64, outputs)
+	buf := make([]byte, 4*(inputs+outputs))
+	for i := 0; i < ds.Len(); i++ {
+		if err := ds.Sample(i, x, y); err != nil {
+			return err
+		}
+		for j, v := range x {
+			binary.LittleEndian.PutUint32(buf[4*j:], math.Float32bits(float32(v)))
+		}
+		for j, v := range y {
+			binary.LittleEndian.PutUint32(buf[4*(inputs+j):], math.Float32bits(float32(v)))
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// OpenFile opens a dataset written by WriteFile. The caller must Close it.
+func OpenFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, fileHeaderSize)
+	if _, err := io.ReadFull(f, header); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("read dataset header: %w", err)
+	}
+	if [4]byte(header[:4]) != fileMagic {
+		f.Close()
+		return nil, errors.New("not a dataset file")
+	}
+
+	d := &File{
+		f:       f,
+		n:       int(binary.LittleEndian.Uint64(header[4:])),
+		inputs:  int(binary.LittleEndian.Uint64(header[12:])),
+		outputs: int(binary.LittleEndian.Uint64(header[20:])),
+	}
+	d.recordSize = int64(4 * (d.inputs + d.outputs))
+	return d, nil
+}
+
+func (d *File) Len() int {
+	return d.n
+}
+
+func (d *File) Dims() (int, int) {
+	return d.inputs, d.outputs
+}
+
+func (d *File) Sample(i int, x, y []float64) error {
+	if i < 0 || i >= d.n {
+		return fmt.Errorf("sample %d out of range [0, %d)", i, d.n)
+	}
+
+	buf := make([]byte, d.recordSize)
+	if _, err := d.f.ReadAt(buf, fileHeaderSize+int64(i)*d.recordSize); err != nil {
+		return fmt.Errorf("read sample %d: %w", i, err)
+	}
+
+	for j := range x {
+		x[j] = float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[4*j:])))
+	}
+	for j := range y {
+		y[j] = float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[4*(d.inputs+j):])))
+	}
+	return nil
+}
+
+func (d *File) Close() error {
+	return d.f.Close()
+}
@@ -0,0 +1,168 @@
+package data
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// IDX is a tensor read from the IDX format used by MNIST. Values are stored
+// in row-major order and converted to float64 regardless of the file's type.
+type IDX struct {
+	Dims []int
+	Data []float64
+}
+
+const mnistClasses = 10
+
+// maxIDXValues bounds the element count accepted from an IDX header, so that
+// a corrupt header fails cleanly instead of triggering a huge allocation. It
+// is well above the 47 million values of the MNIST training images.
+const maxIDXValues = 1 << 28
+
+// ReadIDX decodes an IDX tensor from r. Gzip-compressed input is detected
+// and decompressed transparently.
+func ReadIDX(r io.Reader) (*IDX, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("open gzip stream: %w", err)
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("read idx header: %w", err)
+	}
+	if header[0] != 0 || header[1] != 0 {
+		return nil, fmt.Errorf("invalid idx magic %x", header)
+	}
+
+	typeCode, nDims := header[2], int(header[3])
+	var size int
+	switch typeCode {
+	case 0x08, 0x09:
+		size = 1
+	case 0x0B:
+		size = 2
+	case 0x0C, 0x0D:
+		size = 4
+	case 0x0E:
+		size = 8
+	default:
+		return nil, fmt.Errorf("unsupported idx type 0x%02x", typeCode)
+	}
+
+	idx := &IDX{Dims: make([]int, nDims)}
+	total := 1
+	for i := range idx.Dims {
+		var d uint32
+		if err := binary.Read(br, binary.BigEndian, &d); err != nil {
+			return nil, fmt.Errorf("read idx dimension %d: %w", i, err)
+		}
+		if d != 0 && total > maxIDXValues/int(d) {
+			return nil, fmt.Errorf("idx dimension %d of size %d exceeds %d values in total", i, d, maxIDXValues)
+		}
+		idx.Dims[i] = int(d)
+		total *= int(d)
+	}
+
+	// read through a limit rather than into a buffer sized from the header,
+	// so a truncated file only costs as much memory as it holds
+	raw, err := io.ReadAll(io.LimitReader(br, int64(total*size)))
+	if err != nil {
+		return nil, fmt.Errorf("read idx data: %w", err)
+	}
+	if len(raw) != total*size {
+		return nil, fmt.Errorf("read idx data: %w", io.ErrUnexpectedEOF)
+	}
+
+	idx.Data = make([]float64, total)
+	for i := range idx.Data {
+		b := raw[i*size : (i+1)*size]
+		switch typeCode {
+		case 0x08:
+			idx.Data[i] = float64(b[0])
+		case 0x09:
+			idx.Data[i] = float64(int8(b[0]))
+		case 0x0B:
+			idx.Data[i] = float64(int16(binary.BigEndian.Uint16(b)))
+		case 0x0C:
+			idx.Data[i] = float64(int32(binary.BigEndian.Uint32(b)))
+		case 0x0D:
+			idx.Data[i] = float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+		case 0x0E:
+			idx.Data[i] = math.Float64frombits(binary.BigEndian.Uint64(b))
+		}
+	}
+	return idx, nil
+}
+
+// ReadIDXFile reads an IDX tensor from path, which may be gzip-compressed.
+func ReadIDXFile(path string) (*IDX, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	idx, err := ReadIDX(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return idx, nil
+}
+
+// LoadMNIST reads an MNIST-style image file and its label file. Every image
+// becomes one row of X with pixels scaled to [0, 1], laid out as a single
+// channel of InR x InC as expected by cnn.CNN. Y holds one-hot labels.
+func LoadMNIST(imagesPath, labelsPath string) (X, Y *mat.Dense, err error) {
+	images, err := ReadIDXFile(imagesPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	labels, err := ReadIDXFile(labelsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(images.Dims) != 3 {
+		return nil, nil, fmt.Errorf("%s: expected 3 dimensions, got %d", imagesPath, len(images.Dims))
+	}
+	if len(labels.Dims) != 1 {
+		return nil, nil, fmt.Errorf("%s: expected 1 dimension, got %d", labelsPath, len(labels.Dims))
+	}
+	n := images.Dims[0]
+	if n == 0 || images.Dims[1] == 0 || images.Dims[2] == 0 {
+		return nil, nil, fmt.Errorf("%s: empty image tensor %v", imagesPath, images.Dims)
+	}
+	if labels.Dims[0] != n {
+		return nil, nil, fmt.Errorf("%d images but %d labels", n, labels.Dims[0])
+	}
+
+	pixels := images.Dims[1] * images.Dims[2]
+	xData := make([]float64, len(images.Data))
+	for i, v := range images.Data {
+		xData[i] = v / 255
+	}
+
+	Y = mat.NewDense(n, mnistClasses, nil)
+	for i, v := range labels.Data {
+		label := int(v)
+		if label < 0 || label >= mnistClasses {
+			return nil, nil, fmt.Errorf("%s: label %d of sample %d out of range", labelsPath, label, i)
+		}
+		Y.Set(i, label, 1)
+	}
+
+	return mat.NewDense(n, pixels, xData), Y, nil
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// idxBytes encodes an unsigned byte IDX tensor.
+func idxBytes(dims []int, values []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0, 0, 0x08, byte(len(dims))})
+	for _, d := range dims {
+		binary.Write(&buf, binary.BigEndian, uint32(d))
+	}
+	buf.Write(values)
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, raw []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadMNIST(t *testing.T) {
+	// two 2x3 images and their labels
+	pixels := []byte{0, 51, 102, 153, 204, 255, 255, 0, 0, 0, 0, 255}
+	labels := []byte{3, 9}
+
+	for _, compressed := range []bool{false, true} {
+		images := idxBytes([]int{2, 2, 3}, pixels)
+		labelFile := idxBytes([]int{2}, labels)
+		if compressed {
+			images, labelFile = gzipBytes(t, images), gzipBytes(t, labelFile)
+		}
+
+		X, Y, err := LoadMNIST(writeFixture(t, "images", images), writeFixture(t, "labels", labelFile))
+		if err != nil {
+			t.Fatalf("gzip=%t: %v", compressed, err)
+		}
+
+		if r, c := X.Dims(); r != 2 || c != 6 {
+			t.Errorf("gzip=%t: X dims %dx%d, want 2x6", compressed, r, c)
+		}
+		if r, c := Y.Dims(); r != 2 || c != 10 {
+			t.Errorf("gzip=%t: Y dims %dx%d, want 2x10", compressed, r, c)
+		}
+		for i, p := range pixels {
+			if got, want := X.At(i/6, i%6), float64(p)/255; got != want {
+				t.Errorf("gzip=%t: pixel %d = %v, want %v", compressed, i, got, want)
+			}
+		}
+		for i, label := range labels {
+			for j := 0; j < 10; j++ {
+				want := 0.0
+				if j == int(label) {
+					want = 1
+				}
+				if got := Y.At(i, j); got != want {
+					t.Errorf("gzip=%t: Y[%d][%d] = %v, want %v", compressed, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestReadIDXErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{"bad magic", []byte{1, 2, 0x08, 1, 0, 0, 0, 1, 7}, "invalid idx magic"},
+		{"unsupported type", []byte{0, 0, 0x42, 1, 0, 0, 0, 1, 7}, "unsupported idx type"},
+		{"truncated header", []byte{0, 0}, "read idx header"},
+		{"truncated dims", []byte{0, 0, 0x08, 2, 0, 0, 0, 1}, "read idx dimension 1"},
+		{"truncated data", idxBytes([]int{2, 2}, []byte{1, 2, 3}), "read idx data"},
+		{"oversized header", idxBytes([]int{1 << 16, 1 << 16}, nil), "exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadIDX(bytes.NewReader(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMNISTCountMismatch(t *testing.T) {
+	images := writeFixture(t, "images", idxBytes([]int{2, 1, 1}, []byte{1, 2}))
+	labels := writeFixture(t, "labels", idxBytes([]int{3}, []byte{0, 1, 2}))
+
+	_, _, err := LoadMNIST(images, labels)
+	if err == nil || !strings.Contains(err.Error(), "2 images but 3 labels") {
+		t.Fatalf("got error %v, want an image/label count mismatch", err)
+	}
+}
@@ -0,0 +1,235 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif"}
+
+// ImageConfig describes how decoded images are turned into network inputs.
+type ImageConfig struct {
+	// Rows and Cols are the size every image is resized to, matching the
+	// InR and InC of the first layer.
+	Rows, Cols int
+	// Channels is 1 for grayscale or 3 for RGB.
+	Channels int
+	// Mean and Std, when set, standardize every channel after pixels are
+	// scaled to [0, 1].
+	Mean, Std []float64
+}
+
+type ImageOption func(*ImageConfig)
+
+func WithGrayscale() ImageOption {
+	return func(c *ImageConfig) {
+		c.Channels = 1
+	}
+}
+
+func WithRGB() ImageOption {
+	return func(c *ImageConfig) {
+		c.Channels = 3
+	}
+}
+
+// WithImageNormalization standardizes every channel with the given statistics,
+// for example those returned by ChannelStats on the training set. mean and std
+// must hold one value per channel, or the functions given the option panic.
+func WithImageNormalization(mean, std []float64) ImageOption {
+	return func(c *ImageConfig) {
+		c.Mean = mean
+		c.Std = std
+	}
+}
+
+func newImageConfig(rows, cols int, opts []ImageOption) *ImageConfig {
+	conf := &ImageConfig{Rows: rows, Cols: cols, Channels: 1}
+	for _, opt := range opts {
+		opt(conf)
+	}
+	if (conf.Mean != nil || conf.Std != nil) && (len(conf.Mean) != conf.Channels || len(conf.Std) != conf.Channels) {
+		panic(fmt.Sprintf("image normalization: %d means and %d stds for %d channels", len(conf.Mean), len(conf.Std), conf.Channels))
+	}
+	return conf
+}
+
+// ImageToRow resizes img with bilinear interpolation and returns its pixels
+// as a channel-major row in the layout expected by layer.Conv.
+func ImageToRow(img image.Image, rows, cols int, opts ...ImageOption) []float64 {
+	conf := newImageConfig(rows, cols, opts)
+	row := make([]float64, conf.Channels*rows*cols)
+	imageToRow(img, conf, row)
+	return row
+}
+
+func imageToRow(img image.Image, conf *ImageConfig, row []float64) {
+	bounds := img.Bounds()
+	srcH, srcW := bounds.Dy(), bounds.Dx()
+	channelSize := conf.Rows * conf.Cols
+
+	rgb := func(x, y int) (float64, float64, float64) {
+		r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+		return float64(r) / 0xffff, float64(g) / 0xffff, float64(b) / 0xffff
+	}
+
+	for i := 0; i < conf.Rows; i++ {
+		// map output pixel centres onto source coordinates
+		sy := math.Max(0, (float64(i)+0.5)*float64(srcH)/float64(conf.Rows)-0.5)
+		y0 := int(sy)
+		y1 := min(y0+1, srcH-1)
+		fy := sy - float64(y0)
+
+		for j := 0; j < conf.Cols; j++ {
+			sx := math.Max(0, (float64(j)+0.5)*float64(srcW)/float64(conf.Cols)-0.5)
+			x0 := int(sx)
+			x1 := min(x0+1, srcW-1)
+			fx := sx - float64(x0)
+
+			var px [3]float64
+			for _, s := range []struct {
+				x, y int
+				w    float64
+			}{
+				{x0, y0, (1 - fx) * (1 - fy)},
+				{x1, y0, fx * (1 - fy)},
+				{x0, y1, (1 - fx) * fy},
+				{x1, y1, fx * fy},
+			} {
+				r, g, b := rgb(s.x, s.y)
+				px[0] += s.w * r
+				px[1] += s.w * g
+				px[2] += s.w * b
+			}
+
+			idx := i*conf.Cols + j
+			if conf.Channels == 1 {
+				row[idx] = 0.299*px[0] + 0.587*px[1] + 0.114*px[2]
+			} else {
+				for c := 0; c < 3; c++ {
+					row[c*channelSize+idx] = px[c]
+				}
+			}
+		}
+	}
+
+	if conf.Mean != nil {
+		for c := 0; c < conf.Channels; c++ {
+			std := conf.Std[c]
+			if std == 0 {
+				std = 1
+			}
+			values := row[c*channelSize : (c+1)*channelSize]
+			for k := range values {
+				values[k] = (values[k] - conf.Mean[c]) / std
+			}
+		}
+	}
+}
+
+// LoadImage decodes a PNG, JPEG or GIF file into a channel-major row.
+func LoadImage(path string, rows, cols int, opts ...ImageOption) ([]float64, error) {
+	img, err := decodeImage(path)
+	if err != nil {
+		return nil, err
+	}
+	return ImageToRow(img, rows, cols, opts...), nil
+}
+
+func decodeImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return img, nil
+}
+
+// ImageFolder is a labelled image dataset laid out as one subdirectory per
+// class. Images are decoded lazily in Sample, so the folder can be larger
+// than memory. Targets are one-hot over Classes.
+type ImageFolder struct {
+	Classes []string
+	Paths   []string
+	Labels  []int
+
+	conf *ImageConfig
+}
+
+// NewImageFolder indexes the images under root. Classes are the sorted names
+// of root's subdirectories.
+func NewImageFolder(root string, rows, cols int, opts ...ImageOption) (*ImageFolder, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &ImageFolder{conf: newImageConfig(rows, cols, opts)}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			f.Classes = append(f.Classes, entry.Name())
+		}
+	}
+	sort.Strings(f.Classes)
+
+	for label, class := range f.Classes {
+		files, err := os.ReadDir(filepath.Join(root, class))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			ext := strings.ToLower(filepath.Ext(file.Name()))
+			if file.IsDir() || !slices.Contains(imageExtensions, ext) {
+				continue
+			}
+			f.Paths = append(f.Paths, filepath.Join(root, class, file.Name()))
+			f.Labels = append(f.Labels, label)
+		}
+	}
+
+	if len(f.Paths) == 0 {
+		return nil, errors.New("no images found in " + root)
+	}
+	return f, nil
+}
+
+func (f *ImageFolder) Len() int {
+	return len(f.Paths)
+}
+
+func (f *ImageFolder) Dims() (int, int) {
+	return f.conf.Channels * f.conf.Rows * f.conf.Cols, len(f.Classes)
+}
+
+func (f *ImageFolder) Sample(i int, x, y []float64) error {
+	if i < 0 || i >= len(f.Paths) {
+		return fmt.Errorf("sample %d out of range [0, %d)", i, len(f.Paths))
+	}
+
+	img, err := decodeImage(f.Paths[i])
+	if err != nil {
+		return err
+	}
+	imageToRow(img, f.conf, x)
+
+	for j := range y {
+		y[j] = 0
+	}
+	y[f.Labels[i]] = 1
+	return nil
+}
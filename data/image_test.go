@@ -0,0 +1,136 @@
+package data
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// checker is a 2x2 image with black, red, green and white pixels.
+func checker() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{0, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(0, 1, color.RGBA{0, 255, 0, 255})
+	img.Set(1, 1, color.RGBA{255, 255, 255, 255})
+	return img
+}
+
+func assertRow(t *testing.T, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("row has %d values, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("row %v, want %v", got, want)
+		}
+	}
+}
+
+func TestImageToRow(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows, cols int
+		opts       []ImageOption
+		want       []float64
+	}{
+		// channel-major: all red values, then green, then blue
+		{"rgb", 2, 2, []ImageOption{WithRGB()}, []float64{0, 1, 0, 1, 0, 0, 1, 1, 0, 0, 0, 1}},
+		{"grayscale", 2, 2, nil, []float64{0, 0.299, 0.587, 1}},
+		// one output pixel centred between all four inputs averages them
+		{"downscale", 1, 1, []ImageOption{WithRGB()}, []float64{0.5, 0.5, 0.25}},
+		{
+			"normalized", 1, 1,
+			[]ImageOption{WithRGB(), WithImageNormalization([]float64{0.5, 0.25, 0}, []float64{0.5, 0, 0.25})},
+			[]float64{0, 0.25, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertRow(t, ImageToRow(checker(), tt.rows, tt.cols, tt.opts...), tt.want)
+		})
+	}
+}
+
+func writePNG(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImageFolder(t *testing.T) {
+	root := t.TempDir()
+	for _, class := range []string{"dogs", "cats"} {
+		if err := os.Mkdir(filepath.Join(root, class), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writePNG(t, filepath.Join(root, "cats", "a.png"), checker())
+	writePNG(t, filepath.Join(root, "dogs", "b.PNG"), image.NewGray(image.Rect(0, 0, 3, 3)))
+	// files that are not images are skipped
+	if err := os.WriteFile(filepath.Join(root, "dogs", "notes.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewImageFolder(root, 2, 2, WithRGB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(f.Classes, []string{"cats", "dogs"}) || f.Len() != 2 {
+		t.Fatalf("classes %v with %d images, want [cats dogs] with 2", f.Classes, f.Len())
+	}
+	if inputs, outputs := f.Dims(); inputs != 12 || outputs != 2 {
+		t.Fatalf("dims %d -> %d, want 12 -> 2", inputs, outputs)
+	}
+
+	x, y := make([]float64, 12), make([]float64, 2)
+	if err := f.Sample(1, x, y); err != nil {
+		t.Fatal(err)
+	}
+	assertRow(t, x, make([]float64, 12))
+	assertRow(t, y, []float64{0, 1})
+
+	if err := f.Sample(2, x, y); err == nil {
+		t.Error("Sample(2) succeeded on 2 images")
+	}
+}
+
+func TestImageFolderEmpty(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewImageFolder(root, 2, 2); err == nil {
+		t.Error("NewImageFolder succeeded without images")
+	}
+}
+
+func TestImageNormalizationLength(t *testing.T) {
+	tests := map[string][]ImageOption{
+		"short std":          {WithRGB(), WithImageNormalization([]float64{0, 0, 0}, []float64{1})},
+		"rgb stats for gray": {WithImageNormalization([]float64{0, 0, 0}, []float64{1, 1, 1})},
+		"missing std":        {WithImageNormalization([]float64{0}, nil)},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			ImageToRow(checker(), 2, 2, opts...)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package data
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Iterator walks a dataset in batches. It is used like bufio.Scanner:
+//
+//	it := data.NewIterator(ds, 32)
+//	defer it.Close()
+//	for it.Next() {
+//		x, y := it.Batch()
+//		...
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator interface {
+	Next() bool
+	Batch() (x, y *mat.Dense)
+	Err() error
+	Close()
+}
+
+type IteratorConfig struct {
+	Shuffle bool
+	Rand    *rand.Rand
+}
+
+type IteratorOption func(*IteratorConfig)
+
+// WithShuffle visits samples in a random order drawn from a generator seeded
+// with seed.
+func WithShuffle(seed uint64) IteratorOption {
+	return func(c *IteratorConfig) {
+		c.Shuffle = true
+		c.Rand = rand.New(rand.NewPCG(seed, seed))
+	}
+}
+
+// WithRand visits samples in a random order drawn from r, so consecutive
+// iterators can share one generator and see different orders.
+func WithRand(r *rand.Rand) IteratorOption {
+	return func(c *IteratorConfig) {
+		c.Shuffle = true
+		c.Rand = r
+	}
+}
+
+type batch struct {
+	x, y *mat.Dense
+	err  error
+}
+
+// prefetchIterator assembles the next batch on a background goroutine while
+// the caller works on the current one.
+type prefetchIterator struct {
+	batches chan batch
+	done    chan struct{}
+
+	x, y *mat.Dense
+	err  error
+}
+
+// NewIterator returns an Iterator over ds in batches of batchSize. The last
+// batch holds the remaining samples and may be smaller. It panics if
+// batchSize is not positive.
+func NewIterator(ds Dataset, batchSize int, opts ...IteratorOption) Iterator {
+	if batchSize <= 0 {
+		panic(fmt.Sprintf("iterator: batch size %d is not positive", batchSize))
+	}
+	conf := &IteratorConfig{}
+	for _, opt := range opts {
+		opt(conf)
+	}
+
+	order := make([]int, ds.Len())
+	for i := range order {
+		order[i] = i
+	}
+	if conf.Shuffle {
+		conf.Rand.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+
+	it := &prefetchIterator{
+		batches: make(chan batch, 1),
+		done:    make(chan struct{}),
+	}
+	go it.produce(ds, order, batchSize)
+	return it
+}
+
+func (it *prefetchIterator) produce(ds Dataset, order []int, batchSize int) {
+	defer close(it.batches)
+	inputs, outputs := ds.Dims()
+
+	for start := 0; start < len(order); start += batchSize {
+		end := start + batchSize
+		if end > len(order) {
+			end = len(order)
+		}
+
+		b := batch{
+			x: mat.NewDense(end-start, inputs, nil),
+			y: mat.NewDense(end-start, outputs, nil),
+		}
+		for i, idx := range order[start:end] {
+			if err := ds.Sample(idx, b.x.RawRowView(i), b.y.RawRowView(i)); err != nil {
+				b = batch{err: err}
+				break
+			}
+		}
+
+		select {
+		case it.batches <- b:
+		case <-it.done:
+			return
+		}
+		if b.err != nil {
+			return
+		}
+	}
+}
+
+func (it *prefetchIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	b, ok := <-it.batches
+	if !ok {
+		it.x, it.y = nil, nil
+		return false
+	}
+	if b.err != nil {
+		it.err = b.err
+		it.x, it.y = nil, nil
+		return false
+	}
+	it.x, it.y = b.x, b.y
+	return true
+}
+
+func (it *prefetchIterator) Batch() (*mat.Dense, *mat.Dense) {
+	return it.x, it.y
+}
+
+func (it *prefetchIterator) Err() error {
+	return it.err
+}
+
+// Close stops the background goroutine. It must be called if the iterator is
+// abandoned before Next returns false.
+func (it *prefetchIterator) Close() {
+	select {
+	case <-it.done:
+	default:
+		close(it.done)
+	}
+}
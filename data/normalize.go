@@ -0,0 +1,65 @@
+package data
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ChannelStats returns the mean and standard deviation of every channel of
+// channel-major rows in X.
+func ChannelStats(X *mat.Dense, channels int) (mean, std []float64) {
+	r, c := X.Dims()
+	channelSize := c / channels
+
+	mean = make([]float64, channels)
+	std = make([]float64, channels)
+	for i := 0; i < r; i++ {
+		row := X.RawRowView(i)
+		for ch := 0; ch < channels; ch++ {
+			for _, v := range row[ch*channelSize : (ch+1)*channelSize] {
+				mean[ch] += v
+			}
+		}
+	}
+	count := float64(r * channelSize)
+	for ch := range mean {
+		mean[ch] /= count
+	}
+
+	for i := 0; i < r; i++ {
+		row := X.RawRowView(i)
+		for ch := 0; ch < channels; ch++ {
+			for _, v := range row[ch*channelSize : (ch+1)*channelSize] {
+				d := v - mean[ch]
+				std[ch] += d * d
+			}
+		}
+	}
+	for ch := range std {
+		std[ch] = math.Sqrt(std[ch] / count)
+	}
+	return mean, std
+}
+
+// NormalizeChannels standardizes channel-major rows of X in place using the
+// given per-channel statistics, typically computed on the training set.
+func NormalizeChannels(X *mat.Dense, mean, std []float64) {
+	r, c := X.Dims()
+	channels := len(mean)
+	channelSize := c / channels
+
+	for i := 0; i < r; i++ {
+		row := X.RawRowView(i)
+		for ch := 0; ch < channels; ch++ {
+			s := std[ch]
+			if s == 0 {
+				s = 1
+			}
+			values := row[ch*channelSize : (ch+1)*channelSize]
+			for j := range values {
+				values[j] = (values[j] - mean[ch]) / s
+			}
+		}
+	}
+}
@@ -110,3 +110,166 @@ func FromWindowsMultiChannel(dXCol *mat.Dense, batchSize, numChannels, inR, inC,
 
 	return mat.NewDense(batchSize, inFeatures, data)
 }
+
+// ToWindowsStrided is ToWindowsMultiChannel for kernels moved by stride over
+// the input zero-padded by padding on every side. Window positions falling on
+// the padding read as zero.
+func ToWindowsStrided(inputs *mat.Dense, numChannels, inR, inC, kernelSize, stride, padding int) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	outR := (inR+2*padding-kernelSize)/stride + 1
+	outC := (inC+2*padding-kernelSize)/stride + 1
+	numWindowsPerImage := outR * outC
+
+	windowSize := numChannels * kernelSize * kernelSize
+	totalWindows := batchSize * numWindowsPerImage
+
+	data := make([]float64, windowSize*totalWindows)
+
+	for b := 0; b < batchSize; b++ {
+		inputRow := inputs.RawRowView(b)
+
+		for i := 0; i < outR; i++ {
+			for j := 0; j < outC; j++ {
+				colIdx := b*numWindowsPerImage + i*outC + j
+
+				for c := 0; c < numChannels; c++ {
+					inChannelOffset := c * (inR * inC)
+					windowChannelOffset := c * (kernelSize * kernelSize)
+
+					for ky := 0; ky < kernelSize; ky++ {
+						y := i*stride + ky - padding
+						if y < 0 || y >= inR {
+							continue
+						}
+						for kx := 0; kx < kernelSize; kx++ {
+							x := j*stride + kx - padding
+							if x < 0 || x >= inC {
+								continue
+							}
+
+							rowInMatrix := windowChannelOffset + ky*kernelSize + kx
+							data[rowInMatrix*totalWindows+colIdx] = inputRow[inChannelOffset+y*inC+x]
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return mat.NewDense(windowSize, totalWindows, data)
+}
+
+// FromWindowsStrided is the adjoint of ToWindowsStrided: it sums every window
+// entry back into the input position it was read from, dropping padding.
+func FromWindowsStrided(dXCol *mat.Dense, batchSize, numChannels, inR, inC, kernelSize, stride, padding int) *mat.Dense {
+	outR := (inR+2*padding-kernelSize)/stride + 1
+	outC := (inC+2*padding-kernelSize)/stride + 1
+	numWindowsPerImage := outR * outC
+	inFeatures := numChannels * inR * inC
+
+	data := make([]float64, batchSize*inFeatures)
+	for b := 0; b < batchSize; b++ {
+		gradInRow := data[b*inFeatures : (b+1)*inFeatures]
+
+		for i := 0; i < outR; i++ {
+			for j := 0; j < outC; j++ {
+				colIdx := b*numWindowsPerImage + i*outC + j
+
+				for c := 0; c < numChannels; c++ {
+					inChannelOffset := c * (inR * inC)
+					windowChannelOffset := c * (kernelSize * kernelSize)
+
+					for ky := 0; ky < kernelSize; ky++ {
+						y := i*stride + ky - padding
+						if y < 0 || y >= inR {
+							continue
+						}
+						for kx := 0; kx < kernelSize; kx++ {
+							x := j*stride + kx - padding
+							if x < 0 || x >= inC {
+								continue
+							}
+
+							rowInMatrix := windowChannelOffset + ky*kernelSize + kx
+							gradInRow[inChannelOffset+y*inC+x] += dXCol.At(rowInMatrix, colIdx)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return mat.NewDense(batchSize, inFeatures, data)
+}
+
+// OutLen1D returns the number of windows of kernelSize taps, spaced dilation
+// apart, that fit a sequence of inLen steps padded by padLeft and padRight
+// when moved by stride.
+func OutLen1D(inLen, kernelSize, stride, dilation, padLeft, padRight int) int {
+	return (inLen+padLeft+padRight-dilation*(kernelSize-1)-1)/stride + 1
+}
+
+// ToWindows1D lays out the windows of channel-major sequences of inLen steps
+// as columns, one row per (channel, tap) pair. Taps falling on the padding
+// read as zero.
+func ToWindows1D(inputs *mat.Dense, numChannels, inLen, kernelSize, stride, dilation, padLeft, padRight int) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	outLen := OutLen1D(inLen, kernelSize, stride, dilation, padLeft, padRight)
+
+	windowSize := numChannels * kernelSize
+	totalWindows := batchSize * outLen
+
+	data := make([]float64, windowSize*totalWindows)
+
+	for b := 0; b < batchSize; b++ {
+		inputRow := inputs.RawRowView(b)
+
+		for i := 0; i < outLen; i++ {
+			colIdx := b*outLen + i
+
+			for c := 0; c < numChannels; c++ {
+				for k := 0; k < kernelSize; k++ {
+					t := i*stride + k*dilation - padLeft
+					if t < 0 || t >= inLen {
+						continue
+					}
+
+					rowInMatrix := c*kernelSize + k
+					data[rowInMatrix*totalWindows+colIdx] = inputRow[c*inLen+t]
+				}
+			}
+		}
+	}
+
+	return mat.NewDense(windowSize, totalWindows, data)
+}
+
+// FromWindows1D is the adjoint of ToWindows1D: it sums every window entry
+// back into the step it was read from, dropping padding.
+func FromWindows1D(dXCol *mat.Dense, batchSize, numChannels, inLen, kernelSize, stride, dilation, padLeft, padRight int) *mat.Dense {
+	outLen := OutLen1D(inLen, kernelSize, stride, dilation, padLeft, padRight)
+	inFeatures := numChannels * inLen
+
+	data := make([]float64, batchSize*inFeatures)
+	for b := 0; b < batchSize; b++ {
+		gradInRow := data[b*inFeatures : (b+1)*inFeatures]
+
+		for i := 0; i < outLen; i++ {
+			colIdx := b*outLen + i
+
+			for c := 0; c < numChannels; c++ {
+				for k := 0; k < kernelSize; k++ {
+					t := i*stride + k*dilation - padLeft
+					if t < 0 || t >= inLen {
+						continue
+					}
+
+					rowInMatrix := c*kernelSize + k
+					gradInRow[c*inLen+t] += dXCol.At(rowInMatrix, colIdx)
+				}
+			}
+		}
+	}
+
+	return mat.NewDense(batchSize, inFeatures, data)
+}
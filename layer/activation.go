@@ -0,0 +1,15 @@
+package layer
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func activationString(name string, cache *mat.Dense) string {
+	if cache == nil {
+		return fmt.Sprintf("Activation: %s (not initialized)", name)
+	}
+	_, cols := cache.Dims()
+	return fmt.Sprintf("Activation: %s (Features: %d)", name, cols)
+}
@@ -0,0 +1,66 @@
+package layer
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestActivationGradients(t *testing.T) {
+	tests := map[string]differentiable{
+		"sigmoid":      NewSigmoid(),
+		"leaky relu":   NewLeakyReLU(0.1),
+		"prelu":        NewPReLU(3),
+		"elu":          NewELU(1),
+		"selu":         NewSELU(),
+		"gelu":         NewGELU(),
+		"swish":        NewSwish(1.5),
+		"silu":         NewSiLU(),
+		"softplus":     NewSoftplus(),
+		"hard sigmoid": NewHardSigmoid(),
+	}
+	for name, l := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkInputGradient(t, l, nil, 4, 6)
+		})
+	}
+}
+
+// The slope gradient is summed over the batch like Dense's weight gradient, so
+// a batch of three identical samples moves the slopes three times as far as
+// one sample does.
+func TestPReLUAlphaGradient(t *testing.T) {
+	l := NewPReLU(3)
+	checkParamGradient(t, "alphas", l, l.Alphas, nil, 6)
+
+	single, batch := NewPReLU(2), NewPReLU(2)
+	x := mat.NewDense(1, 4, []float64{-1, 2, -3, -0.5})
+	up := mat.NewDense(1, 4, []float64{1, 1, 0.5, 2})
+	single.Forward(x)
+	single.Backward(up, 0.1)
+
+	xs, ups := mat.NewDense(3, 4, nil), mat.NewDense(3, 4, nil)
+	for i := 0; i < 3; i++ {
+		xs.SetRow(i, x.RawRowView(0))
+		ups.SetRow(i, up.RawRowView(0))
+	}
+	batch.Forward(xs)
+	batch.Backward(ups, 0.1)
+
+	var singleStep, batchStep mat.Dense
+	singleStep.Sub(NewPReLU(2).Alphas, single.Alphas)
+	batchStep.Sub(NewPReLU(2).Alphas, batch.Alphas)
+	singleStep.Scale(3, &singleStep)
+	if !mat.EqualApprox(&singleStep, &batchStep, 1e-12) {
+		t.Errorf("batch alphas %v, single-sample alphas %v", batch.Alphas.RawRowView(0), single.Alphas.RawRowView(0))
+	}
+}
+
+func TestPReLUChannelWidth(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for 5 columns over 2 channels")
+		}
+	}()
+	NewPReLU(2).Forward(mat.NewDense(1, 5, nil))
+}
@@ -0,0 +1,184 @@
+package layer
+
+import "gonum.org/v1/gonum/mat"
+
+// adaptiveBounds returns the half-open input range pooled into output cell i
+// when an axis of length inSize is reduced to outSize cells.
+func adaptiveBounds(i, inSize, outSize int) (int, int) {
+	start := i * inSize / outSize
+	end := ((i+1)*inSize + outSize - 1) / outSize
+	return start, end
+}
+
+// AdaptiveAvgPool averages channel-major feature maps down to a fixed
+// OutR x OutC grid regardless of the input size. Windows may overlap when the
+// input size is not a multiple of the output size.
+type AdaptiveAvgPool struct {
+	InChannels int
+	InR, InC   int
+	OutR, OutC int
+}
+
+func NewAdaptiveAvgPool(outR, outC, inChannels, inR, inC int) *AdaptiveAvgPool {
+	return &AdaptiveAvgPool{
+		InChannels: inChannels,
+		InR:        inR,
+		InC:        inC,
+		OutR:       outR,
+		OutC:       outC,
+	}
+}
+
+func (l *AdaptiveAvgPool) Forward(inputs *mat.Dense) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	outFeatures := l.InChannels * l.OutR * l.OutC
+
+	data := make([]float64, batchSize*outFeatures)
+	for b := 0; b < batchSize; b++ {
+		inputRow := inputs.RawRowView(b)
+		outputRow := data[b*outFeatures : (b+1)*outFeatures]
+
+		for c := 0; c < l.InChannels; c++ {
+			inChannelOffset := c * (l.InR * l.InC)
+			outChannelOffset := c * (l.OutR * l.OutC)
+
+			for i := 0; i < l.OutR; i++ {
+				yStart, yEnd := adaptiveBounds(i, l.InR, l.OutR)
+				for j := 0; j < l.OutC; j++ {
+					xStart, xEnd := adaptiveBounds(j, l.InC, l.OutC)
+
+					sum := 0.0
+					for y := yStart; y < yEnd; y++ {
+						for x := xStart; x < xEnd; x++ {
+							sum += inputRow[inChannelOffset+y*l.InC+x]
+						}
+					}
+					outputRow[outChannelOffset+i*l.OutC+j] = sum / float64((yEnd-yStart)*(xEnd-xStart))
+				}
+			}
+		}
+	}
+
+	return mat.NewDense(batchSize, outFeatures, data)
+}
+
+func (l *AdaptiveAvgPool) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := gradOutput.Dims()
+	inFeatures := l.InChannels * l.InR * l.InC
+
+	gradInputData := make([]float64, batchSize*inFeatures)
+	for b := 0; b < batchSize; b++ {
+		gradOutRow := gradOutput.RawRowView(b)
+		gradInRow := gradInputData[b*inFeatures : (b+1)*inFeatures]
+
+		for c := 0; c < l.InChannels; c++ {
+			inChannelOffset := c * (l.InR * l.InC)
+			outChannelOffset := c * (l.OutR * l.OutC)
+
+			for i := 0; i < l.OutR; i++ {
+				yStart, yEnd := adaptiveBounds(i, l.InR, l.OutR)
+				for j := 0; j < l.OutC; j++ {
+					xStart, xEnd := adaptiveBounds(j, l.InC, l.OutC)
+
+					grad := gradOutRow[outChannelOffset+i*l.OutC+j] / float64((yEnd-yStart)*(xEnd-xStart))
+					for y := yStart; y < yEnd; y++ {
+						for x := xStart; x < xEnd; x++ {
+							gradInRow[inChannelOffset+y*l.InC+x] += grad
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return mat.NewDense(batchSize, inFeatures, gradInputData)
+}
+
+// AdaptiveMaxPool takes the maximum over adaptive windows so that the output
+// is always an OutR x OutC grid per channel.
+type AdaptiveMaxPool struct {
+	InChannels int
+	InR, InC   int
+	OutR, OutC int
+
+	maxIndices []int
+}
+
+func NewAdaptiveMaxPool(outR, outC, inChannels, inR, inC int) *AdaptiveMaxPool {
+	return &AdaptiveMaxPool{
+		InChannels: inChannels,
+		InR:        inR,
+		InC:        inC,
+		OutR:       outR,
+		OutC:       outC,
+	}
+}
+
+func (l *AdaptiveMaxPool) Forward(inputs *mat.Dense) *mat.Dense {
+	out, maxIndices := l.pool(inputs)
+	l.maxIndices = maxIndices
+	return out
+}
+
+func (l *AdaptiveMaxPool) Infer(inputs *mat.Dense) *mat.Dense {
+	out, _ := l.pool(inputs)
+	return out
+}
+
+func (l *AdaptiveMaxPool) pool(inputs *mat.Dense) (*mat.Dense, []int) {
+	batchSize, _ := inputs.Dims()
+	outFeatures := l.InChannels * l.OutR * l.OutC
+
+	data := make([]float64, batchSize*outFeatures)
+	maxIndices := make([]int, batchSize*outFeatures)
+
+	for b := 0; b < batchSize; b++ {
+		inputRow := inputs.RawRowView(b)
+		outputRow := data[b*outFeatures : (b+1)*outFeatures]
+
+		for c := 0; c < l.InChannels; c++ {
+			inChannelOffset := c * (l.InR * l.InC)
+			outChannelOffset := c * (l.OutR * l.OutC)
+
+			for i := 0; i < l.OutR; i++ {
+				yStart, yEnd := adaptiveBounds(i, l.InR, l.OutR)
+				for j := 0; j < l.OutC; j++ {
+					xStart, xEnd := adaptiveBounds(j, l.InC, l.OutC)
+
+					maxIdx := inChannelOffset + yStart*l.InC + xStart
+					for y := yStart; y < yEnd; y++ {
+						for x := xStart; x < xEnd; x++ {
+							pixelIdx := inChannelOffset + y*l.InC + x
+							if inputRow[pixelIdx] > inputRow[maxIdx] {
+								maxIdx = pixelIdx
+							}
+						}
+					}
+
+					outIdx := outChannelOffset + i*l.OutC + j
+					outputRow[outIdx] = inputRow[maxIdx]
+					maxIndices[b*outFeatures+outIdx] = maxIdx
+				}
+			}
+		}
+	}
+
+	return mat.NewDense(batchSize, outFeatures, data), maxIndices
+}
+
+func (l *AdaptiveMaxPool) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, outFeatures := gradOutput.Dims()
+	inFeatures := l.InChannels * l.InR * l.InC
+
+	gradInputData := make([]float64, batchSize*inFeatures)
+	for b := 0; b < batchSize; b++ {
+		gradOutRow := gradOutput.RawRowView(b)
+		gradInRow := gradInputData[b*inFeatures : (b+1)*inFeatures]
+
+		for outIdx := 0; outIdx < outFeatures; outIdx++ {
+			gradInRow[l.maxIndices[b*outFeatures+outIdx]] += gradOutRow[outIdx]
+		}
+	}
+
+	return mat.NewDense(batchSize, inFeatures, gradInputData)
+}
@@ -0,0 +1,205 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// MultiHeadAttention is scaled dot-product self-attention over time-major
+// sequences of Dim-sized tokens. The columns of Wqkv and Bqkv hold the query,
+// key and value projections in that order, Dim columns each; every head
+// attends over its own Dim/Heads slice of them. With Causal set a token only
+// attends to itself and earlier tokens.
+type MultiHeadAttention struct {
+	Dim    int
+	Heads  int
+	Causal bool
+
+	Wqkv *mat.Dense
+	Bqkv *mat.Dense
+	Wo   *mat.Dense
+	Bo   *mat.Dense
+
+	Regularization Regularization
+
+	cache *attentionCache
+}
+
+type attentionCache struct {
+	batch int
+	steps int
+	x     *mat.Dense
+	qkv   *mat.Dense
+	// weights holds the attention matrix of every sample and head, indexed
+	// sample*Heads + head.
+	weights []*mat.Dense
+	o       *mat.Dense
+}
+
+func NewMultiHeadAttention(dim, heads int, causal bool) *MultiHeadAttention {
+	if dim%heads != 0 {
+		panic(fmt.Sprintf("attention dim %d is not divisible by %d heads", dim, heads))
+	}
+	return &MultiHeadAttention{
+		Dim:    dim,
+		Heads:  heads,
+		Causal: causal,
+		Wqkv:   randomInit(dim, 3*dim),
+		Bqkv:   mat.NewDense(1, 3*dim, nil),
+		Wo:     randomInit(dim, dim),
+		Bo:     mat.NewDense(1, dim, nil),
+	}
+}
+
+func (l *MultiHeadAttention) String() string {
+	return fmt.Sprintf("Attention: multi-head (Dim: %d, Heads: %d, Causal: %t)", l.Dim, l.Heads, l.Causal)
+}
+
+// toTokens reshapes a time-major batch into one token of dim columns per row.
+func toTokens(m *mat.Dense, dim int) *mat.Dense {
+	r, c := m.Dims()
+	return mat.NewDense(r*c/dim, dim, mat.DenseCopyOf(m).RawMatrix().Data)
+}
+
+// fromTokens undoes toTokens for a batch of the given size.
+func fromTokens(m *mat.Dense, batch int) *mat.Dense {
+	r, c := m.Dims()
+	return mat.NewDense(batch, r*c/batch, mat.DenseCopyOf(m).RawMatrix().Data)
+}
+
+// block returns the rows of sample b and the columns of part p (0 query,
+// 1 key, 2 value) of head h from a token matrix.
+func (l *MultiHeadAttention) block(m *mat.Dense, steps, b, p, h int) *mat.Dense {
+	dk := l.Dim / l.Heads
+	offset := p*l.Dim + h*dk
+	return m.Slice(b*steps, (b+1)*steps, offset, offset+dk).(*mat.Dense)
+}
+
+func (l *MultiHeadAttention) run(inputs *mat.Dense) *attentionCache {
+	batch, cols := inputs.Dims()
+	steps := cols / l.Dim
+	scale := 1 / math.Sqrt(float64(l.Dim/l.Heads))
+
+	x := toTokens(inputs, l.Dim)
+	qkv := new(mat.Dense)
+	qkv.Mul(x, l.Wqkv)
+	addRowBias(qkv, l.Bqkv)
+
+	o := mat.NewDense(batch*steps, l.Dim, nil)
+	weights := make([]*mat.Dense, batch*l.Heads)
+	for b := 0; b < batch; b++ {
+		for h := 0; h < l.Heads; h++ {
+			a := mat.NewDense(steps, steps, nil)
+			a.Mul(l.block(qkv, steps, b, 0, h), l.block(qkv, steps, b, 1, h).T())
+			for i := 0; i < steps; i++ {
+				row := a.RawRowView(i)
+				for j := range row {
+					row[j] *= scale
+					if l.Causal && j > i {
+						row[j] = math.Inf(-1)
+					}
+				}
+				softmaxInPlace(row)
+			}
+			weights[b*l.Heads+h] = a
+			l.block(o, steps, b, 0, h).Mul(a, l.block(qkv, steps, b, 2, h))
+		}
+	}
+
+	return &attentionCache{
+		batch:   batch,
+		steps:   steps,
+		x:       x,
+		qkv:     qkv,
+		weights: weights,
+		o:       o,
+	}
+}
+
+// softmaxInPlace replaces row with its softmax.
+func softmaxInPlace(row []float64) {
+	maxVal := math.Inf(-1)
+	for _, v := range row {
+		maxVal = math.Max(maxVal, v)
+	}
+	sum := 0.0
+	for j, v := range row {
+		row[j] = math.Exp(v - maxVal)
+		sum += row[j]
+	}
+	for j := range row {
+		row[j] /= sum
+	}
+}
+
+func (l *MultiHeadAttention) output(cache *attentionCache) *mat.Dense {
+	out := new(mat.Dense)
+	out.Mul(cache.o, l.Wo)
+	addRowBias(out, l.Bo)
+	return fromTokens(out, cache.batch)
+}
+
+func (l *MultiHeadAttention) Forward(inputs *mat.Dense) *mat.Dense {
+	l.cache = l.run(inputs)
+	return l.output(l.cache)
+}
+
+func (l *MultiHeadAttention) Infer(inputs *mat.Dense) *mat.Dense {
+	return l.output(l.run(inputs))
+}
+
+func (l *MultiHeadAttention) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	c := l.cache
+	scale := 1 / math.Sqrt(float64(l.Dim/l.Heads))
+	dy := toTokens(upstreamGradient, l.Dim)
+
+	dWo := new(mat.Dense)
+	dWo.Mul(c.o.T(), dy)
+	dO := new(mat.Dense)
+	dO.Mul(dy, l.Wo.T())
+
+	dQKV := mat.NewDense(c.batch*c.steps, 3*l.Dim, nil)
+	for b := 0; b < c.batch; b++ {
+		for h := 0; h < l.Heads; h++ {
+			a := c.weights[b*l.Heads+h]
+			dOh := l.block(dO, c.steps, b, 0, h)
+
+			l.block(dQKV, c.steps, b, 2, h).Mul(a.T(), dOh)
+
+			ds := new(mat.Dense)
+			ds.Mul(dOh, l.block(c.qkv, c.steps, b, 2, h).T())
+			for i := 0; i < c.steps; i++ {
+				aRow, dRow := a.RawRowView(i), ds.RawRowView(i)
+				dot := 0.0
+				for j := range dRow {
+					dot += dRow[j] * aRow[j]
+				}
+				for j := range dRow {
+					dRow[j] = aRow[j] * (dRow[j] - dot) * scale
+				}
+			}
+
+			l.block(dQKV, c.steps, b, 0, h).Mul(ds, l.block(c.qkv, c.steps, b, 1, h))
+			l.block(dQKV, c.steps, b, 1, h).Mul(ds.T(), l.block(c.qkv, c.steps, b, 0, h))
+		}
+	}
+
+	dWqkv := new(mat.Dense)
+	dWqkv.Mul(c.x.T(), dQKV)
+	dx := new(mat.Dense)
+	dx.Mul(dQKV, l.Wqkv.T())
+
+	applyUpdate(l.Wo, dWo, lr)
+	applyUpdate(l.Bo, sumRows(dy), lr)
+	applyUpdate(l.Wqkv, dWqkv, lr)
+	applyUpdate(l.Bqkv, sumRows(dQKV), lr)
+	l.Regularization.apply(lr*float64(c.batch), []*mat.Dense{l.Wqkv, l.Wo}, []*mat.Dense{l.Bqkv, l.Bo})
+
+	return fromTokens(dx, c.batch)
+}
+
+func (l *MultiHeadAttention) Penalty() float64 {
+	return l.Regularization.penalty([]*mat.Dense{l.Wqkv, l.Wo}, []*mat.Dense{l.Bqkv, l.Bo})
+}
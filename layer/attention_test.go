@@ -0,0 +1,98 @@
+package layer
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	attentionDim   = 4
+	attentionHeads = 2
+	attentionSteps = 3
+)
+
+func TestMultiHeadAttentionGradients(t *testing.T) {
+	cols := attentionSteps * attentionDim
+	for _, causal := range []bool{false, true} {
+		t.Run(fmt.Sprintf("causal=%t", causal), func(t *testing.T) {
+			checkInputGradient(t, NewMultiHeadAttention(attentionDim, attentionHeads, causal), nil, 2, cols)
+
+			params := map[string]func(*MultiHeadAttention) *mat.Dense{
+				"Wqkv": func(l *MultiHeadAttention) *mat.Dense { return l.Wqkv },
+				"Bqkv": func(l *MultiHeadAttention) *mat.Dense { return l.Bqkv },
+				"Wo":   func(l *MultiHeadAttention) *mat.Dense { return l.Wo },
+				"Bo":   func(l *MultiHeadAttention) *mat.Dense { return l.Bo },
+			}
+			for name, param := range params {
+				l := NewMultiHeadAttention(attentionDim, attentionHeads, causal)
+				checkParamGradient(t, name, l, param(l), nil, cols)
+			}
+		})
+	}
+}
+
+// With a causal mask, changing a later step must leave earlier outputs as
+// they were.
+func TestMultiHeadAttentionCausal(t *testing.T) {
+	l := NewMultiHeadAttention(attentionDim, attentionHeads, true)
+	x := randomDense(rand.New(rand.NewPCG(5, 6)), 1, attentionSteps*attentionDim)
+	before := mat.DenseCopyOf(l.Forward(x))
+
+	last := (attentionSteps - 1) * attentionDim
+	x.Set(0, last, x.At(0, last)+1)
+	after := l.Forward(x)
+
+	for j := 0; j < last; j++ {
+		if before.At(0, j) != after.At(0, j) {
+			t.Fatalf("output %d changed from %v to %v", j, before.At(0, j), after.At(0, j))
+		}
+	}
+	if mat.Equal(before, after) {
+		t.Error("changing the last step did not change its output")
+	}
+}
+
+func TestPositionalEncodingGradients(t *testing.T) {
+	cols := attentionSteps * attentionDim
+	checkInputGradient(t, NewSinusoidalPositionalEncoding(attentionSteps, attentionDim), nil, 2, cols)
+
+	learned := NewLearnedPositionalEncoding(attentionSteps, attentionDim)
+	checkInputGradient(t, learned, nil, 2, cols)
+	learned = NewLearnedPositionalEncoding(attentionSteps, attentionDim)
+	checkParamGradient(t, "weights", learned, learned.Weights, nil, cols)
+}
+
+func TestSinusoidalPositionalEncoding(t *testing.T) {
+	l := NewSinusoidalPositionalEncoding(2, 2)
+	out := l.Forward(mat.NewDense(1, 4, nil))
+	// position p adds sin(p) and cos(p) for the lowest frequency
+	want := mat.NewDense(1, 4, []float64{0, 1, 0.8414709848078965, 0.5403023058681398})
+	if !mat.EqualApprox(out, want, 1e-12) {
+		t.Errorf("encoding %v, want %v", out.RawRowView(0), want.RawRowView(0))
+	}
+}
+
+func TestTransformerEncoderBlockGradients(t *testing.T) {
+	const hidden = 6
+	cols := attentionSteps * attentionDim
+	for _, causal := range []bool{false, true} {
+		t.Run(fmt.Sprintf("causal=%t", causal), func(t *testing.T) {
+			checkInputGradient(t, NewTransformerEncoderBlock(attentionDim, attentionHeads, hidden, causal), nil, 2, cols)
+
+			params := map[string]func(*TransformerEncoderBlock) *mat.Dense{
+				"Norm1.Gain":     func(l *TransformerEncoderBlock) *mat.Dense { return l.Norm1.Gain },
+				"Attention.Wqkv": func(l *TransformerEncoderBlock) *mat.Dense { return l.Attention.Wqkv },
+				"Norm2.Bias":     func(l *TransformerEncoderBlock) *mat.Dense { return l.Norm2.Bias },
+				"FF1.Weights":    func(l *TransformerEncoderBlock) *mat.Dense { return l.FF1.Weights },
+				"FF2.Biases":     func(l *TransformerEncoderBlock) *mat.Dense { return l.FF2.Biases },
+			}
+			for name, param := range params {
+				l := NewTransformerEncoderBlock(attentionDim, attentionHeads, hidden, causal)
+				checkParamGradient(t, name, l, param(l), nil, cols)
+			}
+		})
+	}
+}
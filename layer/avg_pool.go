@@ -0,0 +1,97 @@
+package layer
+
+import "gonum.org/v1/gonum/mat"
+
+type AvgPool struct {
+	Size   int
+	Stride int
+
+	InChannels int
+	InR, InC   int
+}
+
+func NewAvgPool(size, stride, inChannels, inR, inC int) *AvgPool {
+	return &AvgPool{
+		Size:       size,
+		Stride:     stride,
+		InChannels: inChannels,
+		InR:        inR,
+		InC:        inC,
+	}
+}
+
+func (l *AvgPool) Forward(inputs *mat.Dense) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+
+	outR := (l.InR-l.Size)/l.Stride + 1
+	outC := (l.InC-l.Size)/l.Stride + 1
+
+	outFeatures := l.InChannels * outR * outC
+	data := make([]float64, batchSize*outFeatures)
+	scale := 1.0 / float64(l.Size*l.Size)
+
+	for b := 0; b < batchSize; b++ {
+		inputRow := inputs.RawRowView(b)
+		outputRow := data[b*outFeatures : (b+1)*outFeatures]
+
+		for c := 0; c < l.InChannels; c++ {
+			inChannelOffset := c * (l.InR * l.InC)
+			outChannelOffset := c * (outR * outC)
+
+			for i := 0; i < outR; i++ {
+				for j := 0; j < outC; j++ {
+					inYStart := i * l.Stride
+					inXStart := j * l.Stride
+
+					sum := 0.0
+					for ky := 0; ky < l.Size; ky++ {
+						for kx := 0; kx < l.Size; kx++ {
+							sum += inputRow[inChannelOffset+(inYStart+ky)*l.InC+(inXStart+kx)]
+						}
+					}
+
+					outputRow[outChannelOffset+i*outC+j] = sum * scale
+				}
+			}
+		}
+	}
+
+	return mat.NewDense(batchSize, outFeatures, data)
+}
+
+func (l *AvgPool) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := gradOutput.Dims()
+	inFeatures := l.InChannels * l.InR * l.InC
+
+	outR := (l.InR-l.Size)/l.Stride + 1
+	outC := (l.InC-l.Size)/l.Stride + 1
+	scale := 1.0 / float64(l.Size*l.Size)
+
+	gradInputData := make([]float64, batchSize*inFeatures)
+
+	for b := 0; b < batchSize; b++ {
+		gradOutRow := gradOutput.RawRowView(b)
+		gradInRow := gradInputData[b*inFeatures : (b+1)*inFeatures]
+
+		for c := 0; c < l.InChannels; c++ {
+			inChannelOffset := c * (l.InR * l.InC)
+			outChannelOffset := c * (outR * outC)
+
+			for i := 0; i < outR; i++ {
+				for j := 0; j < outC; j++ {
+					inYStart := i * l.Stride
+					inXStart := j * l.Stride
+					grad := gradOutRow[outChannelOffset+i*outC+j] * scale
+
+					for ky := 0; ky < l.Size; ky++ {
+						for kx := 0; kx < l.Size; kx++ {
+							gradInRow[inChannelOffset+(inYStart+ky)*l.InC+(inXStart+kx)] += grad
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return mat.NewDense(batchSize, inFeatures, gradInputData)
+}
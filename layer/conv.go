@@ -14,6 +14,8 @@ type Conv struct {
 	Kernels *mat.Dense
 	Biases  *mat.Dense
 
+	Regularization Regularization
+
 	lastIm2Col *mat.Dense
 }
 
@@ -35,6 +37,17 @@ func NewConv(kernelSize, kernelsAmount, inChannels, inR, inC int) *Conv {
 	}
 }
 func (l *Conv) Forward(inputs *mat.Dense) *mat.Dense {
+	out, windows := l.convolve(inputs)
+	l.lastIm2Col = windows
+	return out
+}
+
+func (l *Conv) Infer(inputs *mat.Dense) *mat.Dense {
+	out, _ := l.convolve(inputs)
+	return out
+}
+
+func (l *Conv) convolve(inputs *mat.Dense) (*mat.Dense, *mat.Dense) {
 	batchSize, _ := inputs.Dims()
 
 	outR := l.InR - l.KernelSize + 1
@@ -42,7 +55,6 @@ func (l *Conv) Forward(inputs *mat.Dense) *mat.Dense {
 	numWindows := outR * outC
 
 	windows := im2col.ToWindowsMultiChannel(inputs, l.InChannels, l.InR, l.InC, l.KernelSize)
-	l.lastIm2Col = windows
 
 	var rawResult mat.Dense
 	rawResult.Mul(l.Kernels, windows)
@@ -59,7 +71,7 @@ func (l *Conv) Forward(inputs *mat.Dense) *mat.Dense {
 		}
 	}
 
-	return mat.NewDense(batchSize, l.KernelsAmount*numWindows, data)
+	return mat.NewDense(batchSize, l.KernelsAmount*numWindows, data), windows
 }
 
 func (l *Conv) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
@@ -98,6 +110,11 @@ func (l *Conv) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
 	dW.Mul(gradMatrix, l.lastIm2Col.T())
 	dW.Scale(lr*batchScale, &dW)
 	l.Kernels.Sub(l.Kernels, &dW)
+	l.Regularization.apply(lr, []*mat.Dense{l.Kernels}, []*mat.Dense{l.Biases})
 
 	return gradInput
 }
+
+func (l *Conv) Penalty() float64 {
+	return l.Regularization.penalty([]*mat.Dense{l.Kernels}, []*mat.Dense{l.Biases})
+}
@@ -0,0 +1,138 @@
+package layer
+
+import (
+	"github.com/velosypedno/nns/im2col"
+	"gonum.org/v1/gonum/mat"
+)
+
+// One-dimensional layers read channel-major sequences: InChannels blocks of
+// InLen steps per row. Causal padding pads only the start of the sequence so
+// that no output step depends on later input steps.
+
+// padding1D returns the left and right padding of a window of size taps
+// spaced dilation apart.
+func padding1D(size, dilation, padding int, causal bool) (int, int) {
+	if causal {
+		return dilation * (size - 1), 0
+	}
+	return padding, padding
+}
+
+// Conv1D convolves multichannel sequences with KernelsAmount kernels of
+// KernelSize taps spaced Dilation steps apart.
+type Conv1D struct {
+	KernelSize    int
+	KernelsAmount int
+	Stride        int
+	Padding       int
+	Dilation      int
+	Causal        bool
+	InChannels    int
+	InLen         int
+
+	Kernels *mat.Dense
+	Biases  *mat.Dense
+
+	Regularization Regularization
+
+	lastIm2Col *mat.Dense
+}
+
+func NewConv1D(kernelSize, kernelsAmount, stride, padding, dilation, inChannels, inLen int) *Conv1D {
+	return &Conv1D{
+		KernelSize:    kernelSize,
+		KernelsAmount: kernelsAmount,
+		Stride:        stride,
+		Padding:       padding,
+		Dilation:      dilation,
+		InChannels:    inChannels,
+		InLen:         inLen,
+		Kernels:       randomInit(kernelsAmount, inChannels*kernelSize),
+		Biases:        mat.NewDense(1, kernelsAmount, nil),
+	}
+}
+
+// NewCausalConv1D returns a stride 1 Conv1D padded so that every output step
+// only sees the current and earlier input steps, keeping the sequence length.
+func NewCausalConv1D(kernelSize, kernelsAmount, dilation, inChannels, inLen int) *Conv1D {
+	l := NewConv1D(kernelSize, kernelsAmount, 1, 0, dilation, inChannels, inLen)
+	l.Causal = true
+	return l
+}
+
+func (l *Conv1D) geometry() (outLen, padLeft, padRight int) {
+	padLeft, padRight = padding1D(l.KernelSize, l.Dilation, l.Padding, l.Causal)
+	outLen = im2col.OutLen1D(l.InLen, l.KernelSize, l.Stride, l.Dilation, padLeft, padRight)
+	return outLen, padLeft, padRight
+}
+
+func (l *Conv1D) Forward(inputs *mat.Dense) *mat.Dense {
+	out, windows := l.convolve(inputs)
+	l.lastIm2Col = windows
+	return out
+}
+
+func (l *Conv1D) Infer(inputs *mat.Dense) *mat.Dense {
+	out, _ := l.convolve(inputs)
+	return out
+}
+
+func (l *Conv1D) convolve(inputs *mat.Dense) (*mat.Dense, *mat.Dense) {
+	batchSize, _ := inputs.Dims()
+	outLen, padLeft, padRight := l.geometry()
+
+	windows := im2col.ToWindows1D(inputs, l.InChannels, l.InLen, l.KernelSize, l.Stride, l.Dilation, padLeft, padRight)
+
+	var rawResult mat.Dense
+	rawResult.Mul(l.Kernels, windows)
+
+	data := make([]float64, batchSize*l.KernelsAmount*outLen)
+	for b := 0; b < batchSize; b++ {
+		for k := 0; k < l.KernelsAmount; k++ {
+			bias := l.Biases.At(0, k)
+			for t := 0; t < outLen; t++ {
+				data[b*l.KernelsAmount*outLen+k*outLen+t] = rawResult.At(k, b*outLen+t) + bias
+			}
+		}
+	}
+
+	return mat.NewDense(batchSize, l.KernelsAmount*outLen, data), windows
+}
+
+func (l *Conv1D) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := gradOutput.Dims()
+	outLen, padLeft, padRight := l.geometry()
+	batchScale := 1.0 / float64(batchSize)
+
+	gradMatrix := mat.NewDense(l.KernelsAmount, batchSize*outLen, nil)
+	for b := 0; b < batchSize; b++ {
+		row := gradOutput.RawRowView(b)
+		for k := 0; k < l.KernelsAmount; k++ {
+			copy(gradMatrix.RawRowView(k)[b*outLen:(b+1)*outLen], row[k*outLen:(k+1)*outLen])
+		}
+	}
+
+	for k := 0; k < l.KernelsAmount; k++ {
+		var db float64
+		for _, v := range gradMatrix.RawRowView(k) {
+			db += v
+		}
+		l.Biases.Set(0, k, l.Biases.At(0, k)-lr*db*batchScale)
+	}
+
+	var dXCol mat.Dense
+	dXCol.Mul(l.Kernels.T(), gradMatrix)
+	gradInput := im2col.FromWindows1D(&dXCol, batchSize, l.InChannels, l.InLen, l.KernelSize, l.Stride, l.Dilation, padLeft, padRight)
+
+	var dW mat.Dense
+	dW.Mul(gradMatrix, l.lastIm2Col.T())
+	dW.Scale(lr*batchScale, &dW)
+	l.Kernels.Sub(l.Kernels, &dW)
+	l.Regularization.apply(lr, []*mat.Dense{l.Kernels}, []*mat.Dense{l.Biases})
+
+	return gradInput
+}
+
+func (l *Conv1D) Penalty() float64 {
+	return l.Regularization.penalty([]*mat.Dense{l.Kernels}, []*mat.Dense{l.Biases})
+}
@@ -0,0 +1,129 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func causal1D(l differentiable) differentiable {
+	switch l := l.(type) {
+	case *MaxPool1D:
+		l.Causal = true
+	case *AvgPool1D:
+		l.Causal = true
+	}
+	return l
+}
+
+func TestConv1DGradients(t *testing.T) {
+	const inChannels, inLen = 2, 7
+	tests := []struct {
+		name    string
+		newConv func() *Conv1D
+	}{
+		{"plain", func() *Conv1D { return NewConv1D(3, 2, 1, 0, 1, inChannels, inLen) }},
+		{"strided padded", func() *Conv1D { return NewConv1D(3, 2, 2, 1, 1, inChannels, inLen) }},
+		{"dilated", func() *Conv1D { return NewConv1D(2, 3, 1, 0, 3, inChannels, inLen) }},
+		{"causal", func() *Conv1D { return NewCausalConv1D(3, 2, 2, inChannels, inLen) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkInputGradient(t, tt.newConv(), nil, 2, inChannels*inLen)
+
+			l := tt.newConv()
+			checkParamGradient(t, "kernels", l, l.Kernels, nil, inChannels*inLen)
+			l = tt.newConv()
+			checkParamGradient(t, "biases", l, l.Biases, nil, inChannels*inLen)
+		})
+	}
+}
+
+func TestPool1DGradients(t *testing.T) {
+	const inChannels, inLen = 2, 7
+	tests := map[string]differentiable{
+		"max":        NewMaxPool1D(2, 2, 0, 1, inChannels, inLen),
+		"max padded": NewMaxPool1D(3, 2, 1, 2, inChannels, inLen),
+		"max causal": causal1D(NewMaxPool1D(3, 1, 0, 1, inChannels, inLen)),
+		"avg":        NewAvgPool1D(2, 2, 0, 1, inChannels, inLen),
+		"avg padded": NewAvgPool1D(3, 2, 1, 2, inChannels, inLen),
+		"avg causal": causal1D(NewAvgPool1D(3, 1, 0, 1, inChannels, inLen)),
+	}
+	for name, l := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkInputGradient(t, l, nil, 2, inChannels*inLen)
+		})
+	}
+}
+
+// A causal layer keeps the sequence length, and changing input step s must
+// leave every earlier output step untouched.
+func TestCausal1D(t *testing.T) {
+	const inChannels, inLen = 2, 6
+	tests := map[string]differentiable{
+		"conv":     NewCausalConv1D(3, 2, 2, inChannels, inLen),
+		"max pool": causal1D(NewMaxPool1D(3, 1, 0, 1, inChannels, inLen)),
+		"avg pool": causal1D(NewAvgPool1D(2, 1, 0, 2, inChannels, inLen)),
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for name, l := range tests {
+		for s := 0; s < inLen; s++ {
+			t.Run(fmt.Sprintf("%s step %d", name, s), func(t *testing.T) {
+				x := randomDense(rng, 1, inChannels*inLen)
+				before := mat.DenseCopyOf(l.Forward(x))
+				_, outCols := before.Dims()
+				outChannels := outCols / inLen
+				if outCols%inLen != 0 {
+					t.Fatalf("output has %d columns, not a multiple of length %d", outCols, inLen)
+				}
+
+				for c := 0; c < inChannels; c++ {
+					x.Set(0, c*inLen+s, x.At(0, c*inLen+s)+1)
+				}
+				after := l.Forward(x)
+				for c := 0; c < outChannels; c++ {
+					for u := 0; u < s; u++ {
+						if j := c*inLen + u; before.At(0, j) != after.At(0, j) {
+							t.Errorf("output channel %d step %d changed", c, u)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+// Padding as wide as the window would leave edge windows without any input.
+func TestPool1DPadding(t *testing.T) {
+	const inChannels, inLen = 1, 4
+	for name, build := range map[string]func(){
+		"max":         func() { NewMaxPool1D(2, 1, 2, 1, inChannels, inLen) },
+		"avg":         func() { NewAvgPool1D(2, 1, 2, 1, inChannels, inLen) },
+		"avg dilated": func() { NewAvgPool1D(3, 1, 5, 2, inChannels, inLen) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic for padding beyond the window", name)
+				}
+			}()
+			build()
+		}()
+	}
+
+	x := mat.NewDense(1, inLen, []float64{1, 2, 3, 4})
+	for name, l := range map[string]differentiable{
+		"max": NewMaxPool1D(3, 1, 4, 2, inChannels, inLen),
+		"avg": NewAvgPool1D(3, 1, 4, 2, inChannels, inLen),
+	} {
+		for _, v := range l.Forward(x).RawRowView(0) {
+			if math.IsInf(v, 0) || math.IsNaN(v) {
+				t.Errorf("%s: widest valid padding gives %v", name, l.Forward(x).RawRowView(0))
+				break
+			}
+		}
+	}
+}
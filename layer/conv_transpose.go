@@ -0,0 +1,146 @@
+package layer
+
+import (
+	"github.com/velosypedno/nns/im2col"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ConvTranspose is the transposed (fractionally strided) convolution: every
+// input pixel scatters a KernelSize x KernelSize patch per output channel, so
+// the output grows to (InR-1)*Stride - 2*Padding + KernelSize rows, and
+// likewise for columns. It is the gradient of a Conv with the same stride and
+// padding, and uses the same channel-major layout.
+type ConvTranspose struct {
+	KernelSize    int
+	KernelsAmount int
+	Stride        int
+	Padding       int
+	InChannels    int
+	InR, InC      int
+
+	// Kernels has one row per input channel, holding the patch it writes
+	// into every output channel.
+	Kernels *mat.Dense
+	Biases  *mat.Dense
+
+	Regularization Regularization
+
+	lastInputs *mat.Dense
+}
+
+func NewConvTranspose(kernelSize, kernelsAmount, stride, padding, inChannels, inR, inC int) *ConvTranspose {
+	return &ConvTranspose{
+		KernelSize:    kernelSize,
+		KernelsAmount: kernelsAmount,
+		Stride:        stride,
+		Padding:       padding,
+		InChannels:    inChannels,
+		InR:           inR,
+		InC:           inC,
+		Kernels:       randomInit(inChannels, kernelsAmount*kernelSize*kernelSize),
+		Biases:        mat.NewDense(1, kernelsAmount, nil),
+	}
+}
+
+// outSize returns the spatial size of the output.
+func (l *ConvTranspose) outSize() (int, int) {
+	outR := (l.InR-1)*l.Stride - 2*l.Padding + l.KernelSize
+	outC := (l.InC-1)*l.Stride - 2*l.Padding + l.KernelSize
+	return outR, outC
+}
+
+// channelRows lays out a channel-major batch with one row per channel and
+// one column per (sample, pixel) pair.
+func channelRows(inputs *mat.Dense, channels int) *mat.Dense {
+	batchSize, cols := inputs.Dims()
+	pixels := cols / channels
+	out := mat.NewDense(channels, batchSize*pixels, nil)
+	for b := 0; b < batchSize; b++ {
+		row := inputs.RawRowView(b)
+		for c := 0; c < channels; c++ {
+			copy(out.RawRowView(c)[b*pixels:(b+1)*pixels], row[c*pixels:(c+1)*pixels])
+		}
+	}
+	return out
+}
+
+// fromChannelRows undoes channelRows for a batch of the given size.
+func fromChannelRows(m *mat.Dense, batchSize int) *mat.Dense {
+	channels, cols := m.Dims()
+	pixels := cols / batchSize
+	out := mat.NewDense(batchSize, channels*pixels, nil)
+	for b := 0; b < batchSize; b++ {
+		row := out.RawRowView(b)
+		for c := 0; c < channels; c++ {
+			copy(row[c*pixels:(c+1)*pixels], m.RawRowView(c)[b*pixels:(b+1)*pixels])
+		}
+	}
+	return out
+}
+
+func (l *ConvTranspose) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs = channelRows(inputs, l.InChannels)
+	return l.scatter(l.lastInputs, inputs)
+}
+
+func (l *ConvTranspose) Infer(inputs *mat.Dense) *mat.Dense {
+	return l.scatter(channelRows(inputs, l.InChannels), inputs)
+}
+
+func (l *ConvTranspose) scatter(x, inputs *mat.Dense) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	outR, outC := l.outSize()
+
+	var cols mat.Dense
+	cols.Mul(l.Kernels.T(), x)
+	out := im2col.FromWindowsStrided(&cols, batchSize, l.KernelsAmount, outR, outC, l.KernelSize, l.Stride, l.Padding)
+
+	numPixels := outR * outC
+	for b := 0; b < batchSize; b++ {
+		row := out.RawRowView(b)
+		for k := 0; k < l.KernelsAmount; k++ {
+			bias := l.Biases.At(0, k)
+			for p := k * numPixels; p < (k+1)*numPixels; p++ {
+				row[p] += bias
+			}
+		}
+	}
+
+	return out
+}
+
+func (l *ConvTranspose) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := gradOutput.Dims()
+	outR, outC := l.outSize()
+	numPixels := outR * outC
+	batchScale := 1.0 / float64(batchSize)
+
+	for k := 0; k < l.KernelsAmount; k++ {
+		var db float64
+		for b := 0; b < batchSize; b++ {
+			row := gradOutput.RawRowView(b)
+			for p := k * numPixels; p < (k+1)*numPixels; p++ {
+				db += row[p]
+			}
+		}
+		l.Biases.Set(0, k, l.Biases.At(0, k)-lr*db*batchScale)
+	}
+
+	gradCols := im2col.ToWindowsStrided(gradOutput, l.KernelsAmount, outR, outC, l.KernelSize, l.Stride, l.Padding)
+
+	var dX mat.Dense
+	dX.Mul(l.Kernels, gradCols)
+	gradInput := fromChannelRows(&dX, batchSize)
+
+	var dW mat.Dense
+	dW.Mul(l.lastInputs, gradCols.T())
+	dW.Scale(lr*batchScale, &dW)
+	l.Kernels.Sub(l.Kernels, &dW)
+	l.Regularization.apply(lr, []*mat.Dense{l.Kernels}, []*mat.Dense{l.Biases})
+
+	return gradInput
+}
+
+func (l *ConvTranspose) Penalty() float64 {
+	return l.Regularization.penalty([]*mat.Dense{l.Kernels}, []*mat.Dense{l.Biases})
+}
@@ -0,0 +1,83 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestConvTransposeGradients(t *testing.T) {
+	const inChannels, inR, inC = 2, 3, 3
+	tests := []struct{ kernel, stride, padding int }{
+		{2, 1, 0},
+		{3, 2, 0},
+		{3, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("k%d s%d p%d", tt.kernel, tt.stride, tt.padding), func(t *testing.T) {
+			newLayer := func() *ConvTranspose {
+				return NewConvTranspose(tt.kernel, 2, tt.stride, tt.padding, inChannels, inR, inC)
+			}
+			checkInputGradient(t, newLayer(), nil, 2, inChannels*inR*inC)
+
+			l := newLayer()
+			checkParamGradient(t, "kernels", l, l.Kernels, nil, inChannels*inR*inC)
+			l = newLayer()
+			checkParamGradient(t, "biases", l, l.Biases, nil, inChannels*inR*inC)
+		})
+	}
+}
+
+// A stride 2 transposed convolution with a 2x2 kernel of ones copies every
+// input pixel into its own 2x2 output block.
+func TestConvTransposeOutput(t *testing.T) {
+	l := NewConvTranspose(2, 1, 2, 0, 1, 2, 2)
+	l.Kernels = mat.NewDense(1, 4, []float64{1, 1, 1, 1})
+	l.Biases = mat.NewDense(1, 1, []float64{0.5})
+
+	got := l.Forward(mat.NewDense(1, 4, []float64{1, 2, 3, 4}))
+	want := mat.NewDense(1, 16, []float64{
+		1.5, 1.5, 2.5, 2.5,
+		1.5, 1.5, 2.5, 2.5,
+		3.5, 3.5, 4.5, 4.5,
+		3.5, 3.5, 4.5, 4.5,
+	})
+	if !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("output %v, want %v", got.RawRowView(0), want.RawRowView(0))
+	}
+}
+
+func TestUpsampleGradients(t *testing.T) {
+	const inChannels, inR, inC = 2, 3, 2
+	tests := map[string]differentiable{
+		"nearest":  NewNearestUpsample(2, inChannels, inR, inC),
+		"bilinear": NewBilinearUpsample(2, inChannels, inR, inC),
+	}
+	for name, l := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkInputGradient(t, l, nil, 2, inChannels*inR*inC)
+		})
+	}
+}
+
+func TestUpsampleOutput(t *testing.T) {
+	x := mat.NewDense(1, 2, []float64{0, 4})
+	tests := []struct {
+		name string
+		l    differentiable
+		want []float64
+	}{
+		{"nearest", NewNearestUpsample(2, 1, 1, 2), []float64{0, 0, 4, 4, 0, 0, 4, 4}},
+		// half-pixel centres: outputs sit at a quarter and three quarters
+		// between input pixels, clamped at the border
+		{"bilinear", NewBilinearUpsample(2, 1, 1, 2), []float64{0, 1, 3, 4, 0, 1, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.Forward(x); !mat.EqualApprox(got, mat.NewDense(1, len(tt.want), tt.want), 1e-12) {
+				t.Errorf("output %v, want %v", got.RawRowView(0), tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,8 @@ type Dense struct {
 	Weights *mat.Dense
 	Biases  *mat.Dense
 
+	Regularization Regularization
+
 	LastInputs *mat.Dense
 }
 
@@ -47,7 +49,10 @@ func NewDense(r, c int) *Dense {
 
 func (l *Dense) Forward(inputs *mat.Dense) *mat.Dense {
 	l.LastInputs = mat.DenseCopyOf(inputs)
+	return l.Infer(inputs)
+}
 
+func (l *Dense) Infer(inputs *mat.Dense) *mat.Dense {
 	var out mat.Dense
 	out.Mul(inputs, l.Weights)
 
@@ -63,6 +68,14 @@ func (l *Dense) Forward(inputs *mat.Dense) *mat.Dense {
 }
 
 func (l *Dense) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, _ := upstreamGradient.Dims()
+	return l.backward(upstreamGradient, lr, rows)
+}
+
+// backward is Backward for inputs that may hold several rows per sample, such
+// as the tokens fed by TransformerEncoderBlock. The bias step and the
+// regularization are scaled by the number of samples, not rows.
+func (l *Dense) backward(upstreamGradient *mat.Dense, lr float64, samples int) *mat.Dense {
 	var currentGrad mat.Dense
 	currentGrad.Mul(l.LastInputs.T(), upstreamGradient)
 
@@ -83,10 +96,17 @@ func (l *Dense) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
 			gradSum[j] += val
 		}
 	}
+	// the summed bias gradient is averaged over samples, so token rows of
+	// one sample add up instead of being averaged among themselves
 	biasRow := l.Biases.RawRowView(0)
 	for j := range biasRow {
-		biasRow[j] -= (gradSum[j] / float64(rows) * lr)
+		biasRow[j] -= (gradSum[j] / float64(samples) * lr)
 	}
+	l.Regularization.apply(lr*float64(samples), []*mat.Dense{l.Weights}, []*mat.Dense{l.Biases})
 
 	return &downstreamGradient
 }
+
+func (l *Dense) Penalty() float64 {
+	return l.Regularization.penalty([]*mat.Dense{l.Weights}, []*mat.Dense{l.Biases})
+}
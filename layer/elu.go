@@ -0,0 +1,59 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type ELU struct {
+	Alpha float64
+
+	lastInputs  *mat.Dense
+	lastOutputs *mat.Dense
+}
+
+func NewELU(alpha float64) *ELU {
+	return &ELU{Alpha: alpha}
+}
+
+func (l *ELU) String() string {
+	return activationString(fmt.Sprintf("ELU, Alpha: %g", l.Alpha), l.lastOutputs)
+}
+
+func (l *ELU) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs = mat.DenseCopyOf(inputs)
+	out := l.Infer(inputs)
+	l.lastOutputs = out
+	return out
+}
+
+func (l *ELU) Infer(inputs *mat.Dense) *mat.Dense {
+	rows, cols := inputs.Dims()
+	out := mat.NewDense(rows, cols, nil)
+
+	out.Apply(func(_, _ int, v float64) float64 {
+		if v > 0 {
+			return v
+		}
+		return l.Alpha * math.Expm1(v)
+	}, inputs)
+
+	return out
+}
+
+func (l *ELU) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, cols := upstreamGradient.Dims()
+	downstream := mat.NewDense(rows, cols, nil)
+
+	// for x <= 0 the derivative alpha*e^x equals out + alpha
+	downstream.Apply(func(r, c int, v float64) float64 {
+		if l.lastInputs.At(r, c) > 0 {
+			return v
+		}
+		return v * (l.lastOutputs.At(r, c) + l.Alpha)
+	}, upstreamGradient)
+
+	return downstream
+}
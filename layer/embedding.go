@@ -0,0 +1,204 @@
+package layer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Embedding maps integer indices to learnable dense vectors. Each input row
+// holds T indices stored as floats; the output row holds their T vectors one
+// after another (T*Dim columns), the time-major layout read by the recurrent
+// layers. Rows of repeated indices accumulate their gradients across the
+// batch, so the MLP's per-sample learning rate is expected.
+type Embedding struct {
+	VocabSize int
+	Dim       int
+	Weights   *mat.Dense
+	// Frozen disables updates, e.g. to keep pretrained vectors fixed.
+	Frozen bool
+
+	lastIndices []int
+}
+
+func NewEmbedding(vocabSize, dim int) *Embedding {
+	data := make([]float64, vocabSize*dim)
+	for i := range data {
+		data[i] = rand.NormFloat64() * 0.05
+	}
+	return &Embedding{
+		VocabSize: vocabSize,
+		Dim:       dim,
+		Weights:   mat.NewDense(vocabSize, dim, data),
+	}
+}
+
+func (l *Embedding) String() string {
+	return fmt.Sprintf("Embedding [%d x %d] (Frozen: %t)", l.VocabSize, l.Dim, l.Frozen)
+}
+
+func (l *Embedding) indices(inputs *mat.Dense) []int {
+	r, c := inputs.Dims()
+	indices := make([]int, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			idx := int(inputs.At(i, j))
+			if idx < 0 || idx >= l.VocabSize {
+				panic(fmt.Sprintf("embedding: index %d out of range [0, %d)", idx, l.VocabSize))
+			}
+			indices[i*c+j] = idx
+		}
+	}
+	return indices
+}
+
+func (l *Embedding) lookup(indices []int, rows int) *mat.Dense {
+	steps := len(indices) / rows
+	out := mat.NewDense(rows, steps*l.Dim, nil)
+	for i := 0; i < rows; i++ {
+		row := out.RawRowView(i)
+		for t := 0; t < steps; t++ {
+			copy(row[t*l.Dim:(t+1)*l.Dim], l.Weights.RawRowView(indices[i*steps+t]))
+		}
+	}
+	return out
+}
+
+func (l *Embedding) Forward(inputs *mat.Dense) *mat.Dense {
+	r, _ := inputs.Dims()
+	l.lastIndices = l.indices(inputs)
+	return l.lookup(l.lastIndices, r)
+}
+
+func (l *Embedding) Infer(inputs *mat.Dense) *mat.Dense {
+	r, _ := inputs.Dims()
+	return l.lookup(l.indices(inputs), r)
+}
+
+// Backward updates only the rows of Weights looked up in the last Forward.
+// Indices are not differentiable, so the returned gradient is zero.
+func (l *Embedding) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	r, _ := upstreamGradient.Dims()
+	steps := len(l.lastIndices) / r
+
+	if !l.Frozen {
+		grads := make(map[int][]float64)
+		for i := 0; i < r; i++ {
+			row := upstreamGradient.RawRowView(i)
+			for t := 0; t < steps; t++ {
+				idx := l.lastIndices[i*steps+t]
+				g, ok := grads[idx]
+				if !ok {
+					g = make([]float64, l.Dim)
+					grads[idx] = g
+				}
+				for k, v := range row[t*l.Dim : (t+1)*l.Dim] {
+					g[k] += v
+				}
+			}
+		}
+
+		for idx, g := range grads {
+			weights := l.Weights.RawRowView(idx)
+			for k := range weights {
+				weights[k] -= lr * g[k]
+			}
+		}
+	}
+
+	return mat.NewDense(r, steps, nil)
+}
+
+// LoadPretrained reads vectors in word2vec/GloVe text format, one
+// "word v1 v2 ... vDim" per line, and copies the vector of every word found
+// in vocab into its row. An optional word2vec "count dim" header is skipped.
+// It returns the number of rows filled.
+func (l *Embedding) LoadPretrained(r io.Reader, vocab map[string]int) (int, error) {
+	loaded := 0
+	err := readEmbeddingText(r, l.Dim, func(word string, vector []float64) error {
+		idx, ok := vocab[word]
+		if !ok {
+			return nil
+		}
+		if idx < 0 || idx >= l.VocabSize {
+			return fmt.Errorf("word %q maps to index %d out of range [0, %d)", word, idx, l.VocabSize)
+		}
+		copy(l.Weights.RawRowView(idx), vector)
+		loaded++
+		return nil
+	})
+	return loaded, err
+}
+
+// NewEmbeddingFromText builds an embedding holding every vector of a
+// word2vec/GloVe text file, together with the vocabulary mapping each word
+// to its row in file order.
+func NewEmbeddingFromText(r io.Reader) (*Embedding, map[string]int, error) {
+	vocab := make(map[string]int)
+	var data []float64
+	dim := 0
+	err := readEmbeddingText(r, 0, func(word string, vector []float64) error {
+		if _, ok := vocab[word]; ok {
+			return nil
+		}
+		dim = len(vector)
+		vocab[word] = len(vocab)
+		data = append(data, vector...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(vocab) == 0 {
+		return nil, nil, fmt.Errorf("embedding file has no vectors")
+	}
+
+	return &Embedding{
+		VocabSize: len(vocab),
+		Dim:       dim,
+		Weights:   mat.NewDense(len(vocab), dim, data),
+	}, vocab, nil
+}
+
+// readEmbeddingText calls fn for every vector line. When dim is 0 it is taken
+// from the first vector and every later line must match it.
+func readEmbeddingText(r io.Reader, dim int, fn func(word string, vector []float64) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if line == 1 && len(fields) == 2 {
+			if _, err := strconv.Atoi(fields[0]); err == nil {
+				continue
+			}
+		}
+
+		vector := make([]float64, len(fields)-1)
+		for k, f := range fields[1:] {
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return fmt.Errorf("embedding line %d: %w", line, err)
+			}
+			vector[k] = v
+		}
+		if dim == 0 {
+			dim = len(vector)
+		}
+		if len(vector) != dim {
+			return fmt.Errorf("embedding line %d: expected %d values, got %d", line, dim, len(vector))
+		}
+		if err := fn(fields[0], vector); err != nil {
+			return fmt.Errorf("embedding line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
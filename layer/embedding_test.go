@@ -0,0 +1,90 @@
+package layer
+
+import (
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestEmbeddingGradient(t *testing.T) {
+	l := NewEmbedding(5, 3)
+	// index 1 appears twice, so its gradients must accumulate
+	checkParamGradient(t, "weights", l, l.Weights, mat.NewDense(1, 4, []float64{1, 3, 1, 0}), 4)
+}
+
+func TestEmbeddingSparseUpdate(t *testing.T) {
+	l := NewEmbedding(4, 2)
+	before := mat.DenseCopyOf(l.Weights)
+
+	x := mat.NewDense(2, 2, []float64{0, 2, 2, 0})
+	l.Forward(x)
+	up := mat.NewDense(2, 4, []float64{1, 1, 1, 1, 1, 1, 1, 1})
+	if r, c := l.Backward(up, 0.5).Dims(); r != 2 || c != 2 {
+		t.Errorf("input gradient is %dx%d, want 2x2", r, c)
+	}
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 2; j++ {
+			want := before.At(i, j)
+			if i == 0 || i == 2 {
+				// looked up twice with gradient 1 at lr 0.5
+				want--
+			}
+			if got := l.Weights.At(i, j); got != want {
+				t.Errorf("weight (%d,%d) = %v, want %v", i, j, got, want)
+			}
+		}
+	}
+
+	l.Frozen = true
+	frozen := mat.DenseCopyOf(l.Weights)
+	l.Forward(x)
+	l.Backward(up, 0.5)
+	if !mat.Equal(l.Weights, frozen) {
+		t.Error("frozen embedding was updated")
+	}
+}
+
+func TestEmbeddingIndexRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for index 4 in a vocabulary of 4")
+		}
+	}()
+	NewEmbedding(4, 2).Forward(mat.NewDense(1, 1, []float64{4}))
+}
+
+func TestEmbeddingText(t *testing.T) {
+	const vectors = "3 2\nthe 0.1 0.2\ncat 1 2\nthe 9 9\n\nsat -1 0.5\n"
+
+	l, vocab, err := NewEmbeddingFromText(strings.NewReader(vectors))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the header is skipped and the repeated word keeps its first vector
+	want := mat.NewDense(3, 2, []float64{0.1, 0.2, 1, 2, -1, 0.5})
+	if !mat.Equal(l.Weights, want) || vocab["the"] != 0 || vocab["cat"] != 1 || vocab["sat"] != 2 {
+		t.Errorf("weights %v with vocabulary %v", mat.Formatted(l.Weights), vocab)
+	}
+
+	pretrained := NewEmbedding(3, 2)
+	loaded, err := pretrained.LoadPretrained(strings.NewReader(vectors), map[string]int{"cat": 2, "dog": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != 1 || pretrained.Weights.At(2, 0) != 1 || pretrained.Weights.At(2, 1) != 2 {
+		t.Errorf("loaded %d vectors, row 2 = %v", loaded, pretrained.Weights.RawRowView(2))
+	}
+
+	bad := map[string]string{
+		"ragged":   "a 1 2\nb 1\n",
+		"number":   "a 1 x\n",
+		"no words": "\n\n",
+	}
+	for name, text := range bad {
+		if _, _, err := NewEmbeddingFromText(strings.NewReader(text)); err == nil {
+			t.Errorf("%s: NewEmbeddingFromText succeeded", name)
+		}
+	}
+}
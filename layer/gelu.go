@@ -0,0 +1,58 @@
+package layer
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// GELU uses the exact formulation x * Phi(x), where Phi is the standard normal CDF.
+type GELU struct {
+	lastInputs *mat.Dense
+}
+
+func NewGELU() *GELU {
+	return &GELU{}
+}
+
+func (l *GELU) String() string {
+	return activationString("GELU", l.lastInputs)
+}
+
+func (l *GELU) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs = mat.DenseCopyOf(inputs)
+	return l.Infer(inputs)
+}
+
+func (l *GELU) Infer(inputs *mat.Dense) *mat.Dense {
+	rows, cols := inputs.Dims()
+	out := mat.NewDense(rows, cols, nil)
+
+	out.Apply(func(_, _ int, v float64) float64 {
+		return 0.5 * v * (1 + math.Erf(v/math.Sqrt2))
+	}, inputs)
+
+	return out
+}
+
+func (l *GELU) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, cols := upstreamGradient.Dims()
+	downstream := mat.NewDense(rows, cols, nil)
+
+	downstream.Apply(func(r, c int, v float64) float64 {
+		x := l.lastInputs.At(r, c)
+		cdf := 0.5 * (1 + math.Erf(x/math.Sqrt2))
+		pdf := math.Exp(-0.5*x*x) / math.Sqrt(2*math.Pi)
+		return v * (cdf + x*pdf)
+	}, upstreamGradient)
+
+	return downstream
+}
+
+func (l *GELU) GobEncode() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (l *GELU) GobDecode(data []byte) error {
+	return nil
+}
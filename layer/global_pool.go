@@ -0,0 +1,130 @@
+package layer
+
+import "gonum.org/v1/gonum/mat"
+
+// GlobalAvgPool reduces every channel of a channel-major feature map to its
+// mean, producing one feature per channel.
+type GlobalAvgPool struct {
+	InChannels int
+	InR, InC   int
+}
+
+func NewGlobalAvgPool(inChannels, inR, inC int) *GlobalAvgPool {
+	return &GlobalAvgPool{
+		InChannels: inChannels,
+		InR:        inR,
+		InC:        inC,
+	}
+}
+
+func (l *GlobalAvgPool) Forward(inputs *mat.Dense) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	channelSize := l.InR * l.InC
+
+	out := mat.NewDense(batchSize, l.InChannels, nil)
+	for b := 0; b < batchSize; b++ {
+		inputRow := inputs.RawRowView(b)
+		outputRow := out.RawRowView(b)
+
+		for c := 0; c < l.InChannels; c++ {
+			sum := 0.0
+			for _, v := range inputRow[c*channelSize : (c+1)*channelSize] {
+				sum += v
+			}
+			outputRow[c] = sum / float64(channelSize)
+		}
+	}
+
+	return out
+}
+
+func (l *GlobalAvgPool) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := gradOutput.Dims()
+	channelSize := l.InR * l.InC
+
+	gradInput := mat.NewDense(batchSize, l.InChannels*channelSize, nil)
+	for b := 0; b < batchSize; b++ {
+		gradOutRow := gradOutput.RawRowView(b)
+		gradInRow := gradInput.RawRowView(b)
+
+		for c := 0; c < l.InChannels; c++ {
+			grad := gradOutRow[c] / float64(channelSize)
+			for i := c * channelSize; i < (c+1)*channelSize; i++ {
+				gradInRow[i] = grad
+			}
+		}
+	}
+
+	return gradInput
+}
+
+// GlobalMaxPool reduces every channel of a channel-major feature map to its
+// maximum, producing one feature per channel.
+type GlobalMaxPool struct {
+	InChannels int
+	InR, InC   int
+
+	maxIndices []int
+}
+
+func NewGlobalMaxPool(inChannels, inR, inC int) *GlobalMaxPool {
+	return &GlobalMaxPool{
+		InChannels: inChannels,
+		InR:        inR,
+		InC:        inC,
+	}
+}
+
+func (l *GlobalMaxPool) Forward(inputs *mat.Dense) *mat.Dense {
+	out, maxIndices := l.pool(inputs)
+	l.maxIndices = maxIndices
+	return out
+}
+
+func (l *GlobalMaxPool) Infer(inputs *mat.Dense) *mat.Dense {
+	out, _ := l.pool(inputs)
+	return out
+}
+
+func (l *GlobalMaxPool) pool(inputs *mat.Dense) (*mat.Dense, []int) {
+	batchSize, _ := inputs.Dims()
+	channelSize := l.InR * l.InC
+
+	out := mat.NewDense(batchSize, l.InChannels, nil)
+	maxIndices := make([]int, batchSize*l.InChannels)
+
+	for b := 0; b < batchSize; b++ {
+		inputRow := inputs.RawRowView(b)
+		outputRow := out.RawRowView(b)
+
+		for c := 0; c < l.InChannels; c++ {
+			maxIdx := c * channelSize
+			for i := maxIdx + 1; i < (c+1)*channelSize; i++ {
+				if inputRow[i] > inputRow[maxIdx] {
+					maxIdx = i
+				}
+			}
+			outputRow[c] = inputRow[maxIdx]
+			maxIndices[b*l.InChannels+c] = maxIdx
+		}
+	}
+
+	return out, maxIndices
+}
+
+func (l *GlobalMaxPool) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := gradOutput.Dims()
+	inFeatures := l.InChannels * l.InR * l.InC
+
+	gradInput := mat.NewDense(batchSize, inFeatures, nil)
+	for b := 0; b < batchSize; b++ {
+		gradOutRow := gradOutput.RawRowView(b)
+		gradInRow := gradInput.RawRowView(b)
+
+		for c := 0; c < l.InChannels; c++ {
+			gradInRow[l.maxIndices[b*l.InChannels+c]] += gradOutRow[c]
+		}
+	}
+
+	return gradInput
+}
@@ -0,0 +1,111 @@
+package layer
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Gradient checks compare Backward against central differences of the scalar
+// sum(Forward(x) * up) for a random upstream gradient up.
+const (
+	gradStep      = 1e-6
+	gradTolerance = 1e-5
+	// gradLR is small so that a layer updating its parameters while it
+	// backpropagates still sees (almost) the weights the numerical gradient
+	// was taken at.
+	gradLR = 1e-3
+)
+
+// differentiable is the part of a layer the gradient checks drive.
+type differentiable interface {
+	Forward(inputs *mat.Dense) *mat.Dense
+	Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense
+}
+
+func randomDense(rng *rand.Rand, r, c int) *mat.Dense {
+	m := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, rng.NormFloat64())
+		}
+	}
+	return m
+}
+
+// weightedSum returns sum(l.Forward(x) * up).
+func weightedSum(l differentiable, x, up *mat.Dense) float64 {
+	var prod mat.Dense
+	prod.MulElem(l.Forward(x), up)
+	return mat.Sum(&prod)
+}
+
+func assertClose(t *testing.T, what string, i, j int, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > gradTolerance*math.Max(1, math.Max(math.Abs(got), math.Abs(want))) {
+		t.Errorf("%s (%d,%d): analytic %v, numeric %v", what, i, j, got, want)
+	}
+}
+
+// checkInputGradient checks the gradient Backward returns for a random x of
+// rows x cols. x may be nil to draw one.
+func checkInputGradient(t *testing.T, l differentiable, x *mat.Dense, rows, cols int) {
+	t.Helper()
+	rng := rand.New(rand.NewPCG(1, 2))
+	if x == nil {
+		x = randomDense(rng, rows, cols)
+	}
+	outR, outC := l.Forward(x).Dims()
+	up := randomDense(rng, outR, outC)
+	grad := mat.DenseCopyOf(l.Backward(up, 0))
+
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			orig := x.At(i, j)
+			x.Set(i, j, orig+gradStep)
+			plus := weightedSum(l, x, up)
+			x.Set(i, j, orig-gradStep)
+			minus := weightedSum(l, x, up)
+			x.Set(i, j, orig)
+			assertClose(t, "input gradient", i, j, grad.At(i, j), (plus-minus)/(2*gradStep))
+		}
+	}
+}
+
+// checkParamGradient checks the update Backward applies to p, one of l's
+// parameters. It uses a single sample because layers differ in whether they
+// sum or average parameter gradients over the batch.
+func checkParamGradient(t *testing.T, name string, l differentiable, p *mat.Dense, x *mat.Dense, cols int) {
+	t.Helper()
+	rng := rand.New(rand.NewPCG(3, 4))
+	if x == nil {
+		x = randomDense(rng, 1, cols)
+	}
+	outR, outC := l.Forward(x).Dims()
+	up := randomDense(rng, outR, outC)
+
+	pr, pc := p.Dims()
+	numeric := mat.NewDense(pr, pc, nil)
+	for i := 0; i < pr; i++ {
+		for j := 0; j < pc; j++ {
+			orig := p.At(i, j)
+			p.Set(i, j, orig+gradStep)
+			plus := weightedSum(l, x, up)
+			p.Set(i, j, orig-gradStep)
+			minus := weightedSum(l, x, up)
+			p.Set(i, j, orig)
+			numeric.Set(i, j, (plus-minus)/(2*gradStep))
+		}
+	}
+
+	before := mat.DenseCopyOf(p)
+	l.Forward(x)
+	l.Backward(up, gradLR)
+	for i := 0; i < pr; i++ {
+		for j := 0; j < pc; j++ {
+			assertClose(t, name, i, j, (before.At(i, j)-p.At(i, j))/gradLR, numeric.At(i, j))
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package layer
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// GroupedConv splits the input channels into Groups equal groups and convolves
+// each with its own KernelsAmount/Groups kernels, so every kernel only sees
+// the channels of its group. Channel-major rows keep each group contiguous,
+// and the group outputs are concatenated in group order.
+type GroupedConv struct {
+	KernelSize    int
+	KernelsAmount int
+	Groups        int
+	InChannels    int
+	InR, InC      int
+
+	Convs []*Conv
+
+	// Regularization, when not the zero value, replaces the settings of
+	// every group's Conv.
+	Regularization Regularization
+}
+
+func NewGroupedConv(kernelSize, kernelsAmount, groups, inChannels, inR, inC int) *GroupedConv {
+	if inChannels%groups != 0 || kernelsAmount%groups != 0 {
+		panic(fmt.Sprintf("grouped conv: %d channels and %d kernels are not divisible into %d groups", inChannels, kernelsAmount, groups))
+	}
+
+	convs := make([]*Conv, groups)
+	for g := range convs {
+		convs[g] = NewConv(kernelSize, kernelsAmount/groups, inChannels/groups, inR, inC)
+	}
+	return &GroupedConv{
+		KernelSize:    kernelSize,
+		KernelsAmount: kernelsAmount,
+		Groups:        groups,
+		InChannels:    inChannels,
+		InR:           inR,
+		InC:           inC,
+		Convs:         convs,
+	}
+}
+
+// NewDepthwiseConv returns a GroupedConv with one group per input channel,
+// each filtered by multiplier kernels of its own.
+func NewDepthwiseConv(kernelSize, multiplier, inChannels, inR, inC int) *GroupedConv {
+	return NewGroupedConv(kernelSize, multiplier*inChannels, inChannels, inChannels, inR, inC)
+}
+
+func (l *GroupedConv) groupInputs(inputs *mat.Dense, g int) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	width := l.InChannels / l.Groups * l.InR * l.InC
+	return inputs.Slice(0, batchSize, g*width, (g+1)*width).(*mat.Dense)
+}
+
+func (l *GroupedConv) outWidth() int {
+	outR := l.InR - l.KernelSize + 1
+	outC := l.InC - l.KernelSize + 1
+	return l.KernelsAmount / l.Groups * outR * outC
+}
+
+func (l *GroupedConv) run(inputs *mat.Dense, conv func(c *Conv, x *mat.Dense) *mat.Dense) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	width := l.outWidth()
+	out := mat.NewDense(batchSize, l.Groups*width, nil)
+	for g, c := range l.Convs {
+		out.Slice(0, batchSize, g*width, (g+1)*width).(*mat.Dense).Copy(conv(c, l.groupInputs(inputs, g)))
+	}
+	return out
+}
+
+func (l *GroupedConv) Forward(inputs *mat.Dense) *mat.Dense {
+	return l.run(inputs, (*Conv).Forward)
+}
+
+func (l *GroupedConv) Infer(inputs *mat.Dense) *mat.Dense {
+	return l.run(inputs, (*Conv).Infer)
+}
+
+func (l *GroupedConv) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := gradOutput.Dims()
+	width := l.outWidth()
+	gradInput := mat.NewDense(batchSize, l.InChannels*l.InR*l.InC, nil)
+	l.regularize()
+	for g, c := range l.Convs {
+		grad := gradOutput.Slice(0, batchSize, g*width, (g+1)*width).(*mat.Dense)
+		l.groupInputs(gradInput, g).Copy(c.Backward(grad, lr))
+	}
+	return gradInput
+}
+
+// DepthwiseSeparableConv factorizes a Conv into a depthwise convolution that
+// filters every channel on its own followed by a 1x1 pointwise Conv that
+// mixes the channels, as in MobileNet.
+type DepthwiseSeparableConv struct {
+	Depthwise *GroupedConv
+	Pointwise *Conv
+
+	// Regularization, when not the zero value, replaces the settings of both
+	// convolutions.
+	Regularization Regularization
+}
+
+func NewDepthwiseSeparableConv(kernelSize, kernelsAmount, inChannels, inR, inC int) *DepthwiseSeparableConv {
+	outR := inR - kernelSize + 1
+	outC := inC - kernelSize + 1
+	return &DepthwiseSeparableConv{
+		Depthwise: NewDepthwiseConv(kernelSize, 1, inChannels, inR, inC),
+		Pointwise: NewConv(1, kernelsAmount, inChannels, outR, outC),
+	}
+}
+
+func (l *DepthwiseSeparableConv) Forward(inputs *mat.Dense) *mat.Dense {
+	return l.Pointwise.Forward(l.Depthwise.Forward(inputs))
+}
+
+func (l *DepthwiseSeparableConv) Infer(inputs *mat.Dense) *mat.Dense {
+	return l.Pointwise.Infer(l.Depthwise.Infer(inputs))
+}
+
+func (l *DepthwiseSeparableConv) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	l.regularize()
+	return l.Depthwise.Backward(l.Pointwise.Backward(gradOutput, lr), lr)
+}
+
+func (l *GroupedConv) regularize() {
+	for _, c := range l.Convs {
+		l.Regularization.forward(&c.Regularization)
+	}
+}
+
+func (l *GroupedConv) Penalty() float64 {
+	l.regularize()
+	total := 0.0
+	for _, c := range l.Convs {
+		total += c.Penalty()
+	}
+	return total
+}
+
+func (l *DepthwiseSeparableConv) regularize() {
+	l.Regularization.forward(&l.Depthwise.Regularization, &l.Pointwise.Regularization)
+}
+
+func (l *DepthwiseSeparableConv) Penalty() float64 {
+	l.regularize()
+	return l.Depthwise.Penalty() + l.Pointwise.Penalty()
+}
@@ -0,0 +1,81 @@
+package layer
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestGroupedConvGradients(t *testing.T) {
+	const inChannels, inR, inC = 4, 4, 4
+	cols := inChannels * inR * inC
+
+	tests := map[string]func() (differentiable, map[string]*mat.Dense){
+		"grouped": func() (differentiable, map[string]*mat.Dense) {
+			l := NewGroupedConv(3, 6, 2, inChannels, inR, inC)
+			return l, map[string]*mat.Dense{"Convs[1].Kernels": l.Convs[1].Kernels, "Convs[0].Biases": l.Convs[0].Biases}
+		},
+		"depthwise": func() (differentiable, map[string]*mat.Dense) {
+			l := NewDepthwiseConv(2, 2, inChannels, inR, inC)
+			return l, map[string]*mat.Dense{"Convs[3].Kernels": l.Convs[3].Kernels}
+		},
+		"separable": func() (differentiable, map[string]*mat.Dense) {
+			l := NewDepthwiseSeparableConv(3, 5, inChannels, inR, inC)
+			return l, map[string]*mat.Dense{"Depthwise.Convs[2].Kernels": l.Depthwise.Convs[2].Kernels, "Pointwise.Kernels": l.Pointwise.Kernels}
+		},
+	}
+	for name, newLayer := range tests {
+		t.Run(name, func(t *testing.T) {
+			l, params := newLayer()
+			checkInputGradient(t, l, nil, 2, cols)
+
+			for param := range params {
+				l, params := newLayer()
+				checkParamGradient(t, param, l, params[param], nil, cols)
+			}
+		})
+	}
+}
+
+// Every group only sees its own slice of the input channels.
+func TestGroupedConvIsolatesGroups(t *testing.T) {
+	l := NewGroupedConv(3, 4, 2, 2, 4, 4)
+	x := randomDense(rand.New(rand.NewPCG(1, 2)), 1, 2*16)
+	before := mat.DenseCopyOf(l.Forward(x))
+
+	// change the second input channel, which belongs to the second group
+	x.Set(0, 16+5, x.At(0, 16+5)+1)
+	after := l.Forward(x)
+
+	// the first group writes the first two output channels of 2x2 pixels
+	for j := 0; j < 8; j++ {
+		if before.At(0, j) != after.At(0, j) {
+			t.Fatalf("output %d of the first group changed", j)
+		}
+	}
+	if mat.Equal(before, after) {
+		t.Error("changing the second group's input did not change its output")
+	}
+}
+
+// With a single group the layer is a plain Conv.
+func TestGroupedConvSingleGroup(t *testing.T) {
+	grouped := NewGroupedConv(3, 2, 1, 2, 4, 4)
+	conv := NewConv(3, 2, 2, 4, 4)
+	conv.Kernels = mat.DenseCopyOf(grouped.Convs[0].Kernels)
+
+	x := randomDense(rand.New(rand.NewPCG(3, 4)), 2, 2*16)
+	if got, want := grouped.Forward(x), conv.Forward(x); !mat.EqualApprox(got, want, 1e-12) {
+		t.Error("single-group conv differs from Conv")
+	}
+}
+
+func TestGroupedConvDivisibility(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for 3 channels in 2 groups")
+		}
+	}()
+	NewGroupedConv(3, 4, 2, 3, 4, 4)
+}
@@ -0,0 +1,169 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// GRU is a gated recurrent unit layer. The columns of Wx, Wh and Biases hold
+// the reset, update and candidate parts in that order, Hidden columns each:
+//
+//	r = sigmoid(x*Wxr + h*Whr + br)
+//	z = sigmoid(x*Wxz + h*Whz + bz)
+//	n = tanh(x*Wxn + bn + r*(h*Whn))
+//	h' = (1-z)*n + z*h
+type GRU struct {
+	Features        int
+	Hidden          int
+	ReturnSequences bool
+
+	Wx     *mat.Dense
+	Wh     *mat.Dense
+	Biases *mat.Dense
+
+	Regularization Regularization
+
+	cache *gruCache
+}
+
+type gruCache struct {
+	xs []*mat.Dense
+	hs []*mat.Dense
+	// gates holds the activated r, z, n of every step side by side.
+	gates []*mat.Dense
+	// hn holds h*Whn of every step, needed for the reset gate gradient.
+	hn []*mat.Dense
+}
+
+func NewGRU(features, hidden int, returnSequences bool) *GRU {
+	return &GRU{
+		Features:        features,
+		Hidden:          hidden,
+		ReturnSequences: returnSequences,
+		Wx:              randomInit(features, 3*hidden),
+		Wh:              randomInit(hidden, 3*hidden),
+		Biases:          mat.NewDense(1, 3*hidden, nil),
+	}
+}
+
+func (l *GRU) String() string {
+	return fmt.Sprintf("Recurrent: GRU [%d -> %d] (Return sequences: %t)", l.Features, l.Hidden, l.ReturnSequences)
+}
+
+func (l *GRU) run(inputs *mat.Dense) *gruCache {
+	batchSize, cols := inputs.Dims()
+	steps := sequenceSteps("gru", cols, l.Features)
+	h := l.Hidden
+
+	cache := &gruCache{
+		xs:    make([]*mat.Dense, steps),
+		hs:    make([]*mat.Dense, steps+1),
+		gates: make([]*mat.Dense, steps),
+		hn:    make([]*mat.Dense, steps),
+	}
+	cache.hs[0] = mat.NewDense(batchSize, h, nil)
+
+	for t := 0; t < steps; t++ {
+		cache.xs[t] = mat.DenseCopyOf(timeStep(inputs, t, l.Features))
+		hPrev := cache.hs[t]
+
+		var xz, hz mat.Dense
+		xz.Mul(cache.xs[t], l.Wx)
+		addRowBias(&xz, l.Biases)
+		hz.Mul(hPrev, l.Wh)
+
+		gates := mat.NewDense(batchSize, 3*h, nil)
+		hn := mat.NewDense(batchSize, h, nil)
+		hNext := mat.NewDense(batchSize, h, nil)
+		for b := 0; b < batchSize; b++ {
+			x, hh, g := xz.RawRowView(b), hz.RawRowView(b), gates.RawRowView(b)
+			for j := 0; j < h; j++ {
+				r := sigmoid(x[j] + hh[j])
+				z := sigmoid(x[h+j] + hh[h+j])
+				n := math.Tanh(x[2*h+j] + r*hh[2*h+j])
+				g[j], g[h+j], g[2*h+j] = r, z, n
+				hn.Set(b, j, hh[2*h+j])
+				hNext.Set(b, j, (1-z)*n+z*hPrev.At(b, j))
+			}
+		}
+		cache.gates[t] = gates
+		cache.hn[t] = hn
+		cache.hs[t+1] = hNext
+	}
+	return cache
+}
+
+func (l *GRU) Forward(inputs *mat.Dense) *mat.Dense {
+	l.cache = l.run(inputs)
+	return collectSteps(l.cache.hs[1:], l.ReturnSequences)
+}
+
+func (l *GRU) Infer(inputs *mat.Dense) *mat.Dense {
+	cache := l.run(inputs)
+	return collectSteps(cache.hs[1:], l.ReturnSequences)
+}
+
+func (l *GRU) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	steps := len(l.cache.xs)
+	batchSize, _ := l.cache.xs[0].Dims()
+	h := l.Hidden
+
+	dWx := mat.NewDense(l.Features, 3*h, nil)
+	dWh := mat.NewDense(h, 3*h, nil)
+	dB := mat.NewDense(1, 3*h, nil)
+	gradInput := mat.NewDense(batchSize, steps*l.Features, nil)
+	dhNext := mat.NewDense(batchSize, h, nil)
+
+	for t := steps - 1; t >= 0; t-- {
+		gates, hn, hPrev := l.cache.gates[t], l.cache.hn[t], l.cache.hs[t]
+
+		var dh mat.Dense
+		dh.Add(stepGradient(upstreamGradient, t, steps, h, l.ReturnSequences), dhNext)
+
+		// dx and dhh are the pre-activation gradients of the input and
+		// hidden projections; they differ only in the candidate part.
+		dx := mat.NewDense(batchSize, 3*h, nil)
+		dhh := mat.NewDense(batchSize, 3*h, nil)
+		dhDirect := mat.NewDense(batchSize, h, nil)
+		for b := 0; b < batchSize; b++ {
+			g, gx, gh := gates.RawRowView(b), dx.RawRowView(b), dhh.RawRowView(b)
+			for j := 0; j < h; j++ {
+				r, z, n := g[j], g[h+j], g[2*h+j]
+				dhv := dh.At(b, j)
+
+				dn := dhv * (1 - z) * (1 - n*n)
+				dz := dhv * (hPrev.At(b, j) - n) * z * (1 - z)
+				dr := dn * hn.At(b, j) * r * (1 - r)
+
+				gx[j], gx[h+j], gx[2*h+j] = dr, dz, dn
+				gh[j], gh[h+j], gh[2*h+j] = dr, dz, dn*r
+				dhDirect.Set(b, j, dhv*z)
+			}
+		}
+
+		var dWxT, dWhT mat.Dense
+		dWxT.Mul(l.cache.xs[t].T(), dx)
+		dWx.Add(dWx, &dWxT)
+		dWhT.Mul(hPrev.T(), dhh)
+		dWh.Add(dWh, &dWhT)
+		dB.Add(dB, sumRows(dx))
+
+		timeStep(gradInput, t, l.Features).Mul(dx, l.Wx.T())
+		dhNext = new(mat.Dense)
+		dhNext.Mul(dhh, l.Wh.T())
+		dhNext.Add(dhNext, dhDirect)
+	}
+
+	applyUpdate(l.Wx, dWx, lr)
+	applyUpdate(l.Wh, dWh, lr)
+	applyUpdate(l.Biases, dB, lr)
+	l.Regularization.apply(lr*float64(batchSize), []*mat.Dense{l.Wx, l.Wh}, []*mat.Dense{l.Biases})
+
+	return gradInput
+}
+
+func (l *GRU) Penalty() float64 {
+	return l.Regularization.penalty([]*mat.Dense{l.Wx, l.Wh}, []*mat.Dense{l.Biases})
+}
@@ -0,0 +1,64 @@
+package layer
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// HardSigmoid is the piecewise linear approximation clip((x + 3) / 6, 0, 1).
+type HardSigmoid struct {
+	lastInputs *mat.Dense
+}
+
+func NewHardSigmoid() *HardSigmoid {
+	return &HardSigmoid{}
+}
+
+func (l *HardSigmoid) String() string {
+	return activationString("HardSigmoid", l.lastInputs)
+}
+
+func (l *HardSigmoid) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs = mat.DenseCopyOf(inputs)
+	return l.Infer(inputs)
+}
+
+func (l *HardSigmoid) Infer(inputs *mat.Dense) *mat.Dense {
+	rows, cols := inputs.Dims()
+	out := mat.NewDense(rows, cols, nil)
+
+	out.Apply(func(_, _ int, v float64) float64 {
+		switch {
+		case v <= -3:
+			return 0
+		case v >= 3:
+			return 1
+		default:
+			return (v + 3) / 6
+		}
+	}, inputs)
+
+	return out
+}
+
+func (l *HardSigmoid) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, cols := upstreamGradient.Dims()
+	downstream := mat.NewDense(rows, cols, nil)
+
+	downstream.Apply(func(r, c int, v float64) float64 {
+		x := l.lastInputs.At(r, c)
+		if x <= -3 || x >= 3 {
+			return 0
+		}
+		return v / 6
+	}, upstreamGradient)
+
+	return downstream
+}
+
+func (l *HardSigmoid) GobEncode() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (l *HardSigmoid) GobDecode(data []byte) error {
+	return nil
+}
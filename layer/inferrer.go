@@ -0,0 +1,19 @@
+package layer
+
+import "gonum.org/v1/gonum/mat"
+
+// Inferrer is implemented by layers whose Forward caches state for Backward.
+// Infer returns the same output as Forward without touching that state, so a
+// model can be evaluated between training steps.
+type Inferrer interface {
+	Infer(inputs *mat.Dense) *mat.Dense
+}
+
+// Infer runs l in inference mode when it implements Inferrer and falls back to
+// Forward otherwise.
+func Infer(l Layer, inputs *mat.Dense) *mat.Dense {
+	if inf, ok := l.(Inferrer); ok {
+		return inf.Infer(inputs)
+	}
+	return l.Forward(inputs)
+}
@@ -0,0 +1,151 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LayerNorm normalizes every block of Features consecutive columns to zero
+// mean and unit variance, then applies a learnable gain and bias. With
+// Features equal to the input width each sample is normalized as a whole; for
+// time-major sequences of Features-sized tokens each token is normalized on
+// its own. Statistics never span samples, so any batch size, including 1,
+// gives the same per-sample result. The gain and bias gradients are summed
+// over the batch, as for Dense weights.
+type LayerNorm struct {
+	Features int
+	Epsilon  float64
+	Gain     *mat.Dense
+	Bias     *mat.Dense
+
+	lastNormalized *mat.Dense
+	lastInvStd     []float64
+}
+
+func NewLayerNorm(features int) *LayerNorm {
+	gain := make([]float64, features)
+	for i := range gain {
+		gain[i] = 1
+	}
+	return &LayerNorm{
+		Features: features,
+		Epsilon:  1e-5,
+		Gain:     mat.NewDense(1, features, gain),
+		Bias:     mat.NewDense(1, features, nil),
+	}
+}
+
+func (l *LayerNorm) String() string {
+	gainStr := fmt.Sprintf("%v", mat.Formatted(l.Gain, mat.Prefix("    "), mat.Squeeze()))
+	biasStr := fmt.Sprintf("%v", mat.Formatted(l.Bias, mat.Prefix("    "), mat.Squeeze()))
+
+	return fmt.Sprintf(
+		"LayerNorm (Features: %d, Epsilon: %g):\n  Gain:\n%s\n  Bias:\n%s",
+		l.Features, l.Epsilon, gainStr, biasStr,
+	)
+}
+
+// normalize returns the standardized inputs and the inverse standard
+// deviation of every block.
+func (l *LayerNorm) normalize(inputs *mat.Dense) (*mat.Dense, []float64) {
+	r, c := inputs.Dims()
+	if c%l.Features != 0 {
+		panic(fmt.Sprintf("layer norm: %d input columns are not a multiple of %d features", c, l.Features))
+	}
+	blocks := c / l.Features
+	normalized := mat.NewDense(r, c, nil)
+	invStd := make([]float64, r*blocks)
+
+	for i := 0; i < r; i++ {
+		in := inputs.RawRowView(i)
+		out := normalized.RawRowView(i)
+		for k := 0; k < blocks; k++ {
+			block := in[k*l.Features : (k+1)*l.Features]
+
+			mean := 0.0
+			for _, v := range block {
+				mean += v
+			}
+			mean /= float64(l.Features)
+
+			variance := 0.0
+			for _, v := range block {
+				variance += (v - mean) * (v - mean)
+			}
+			variance /= float64(l.Features)
+
+			inv := 1 / math.Sqrt(variance+l.Epsilon)
+			invStd[i*blocks+k] = inv
+			for j, v := range block {
+				out[k*l.Features+j] = (v - mean) * inv
+			}
+		}
+	}
+	return normalized, invStd
+}
+
+func (l *LayerNorm) scale(normalized *mat.Dense) *mat.Dense {
+	r, c := normalized.Dims()
+	out := mat.NewDense(r, c, nil)
+	gain, bias := l.Gain.RawRowView(0), l.Bias.RawRowView(0)
+	out.Apply(func(_, j int, v float64) float64 {
+		f := j % l.Features
+		return v*gain[f] + bias[f]
+	}, normalized)
+	return out
+}
+
+func (l *LayerNorm) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastNormalized, l.lastInvStd = l.normalize(inputs)
+	return l.scale(l.lastNormalized)
+}
+
+func (l *LayerNorm) Infer(inputs *mat.Dense) *mat.Dense {
+	normalized, _ := l.normalize(inputs)
+	return l.scale(normalized)
+}
+
+func (l *LayerNorm) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	r, c := upstreamGradient.Dims()
+	blocks := c / l.Features
+	n := float64(l.Features)
+	gain, bias := l.Gain.RawRowView(0), l.Bias.RawRowView(0)
+
+	dGain := make([]float64, l.Features)
+	dBias := make([]float64, l.Features)
+	downstream := mat.NewDense(r, c, nil)
+	dxhat := make([]float64, l.Features)
+
+	for i := 0; i < r; i++ {
+		up := upstreamGradient.RawRowView(i)
+		xhat := l.lastNormalized.RawRowView(i)
+		down := downstream.RawRowView(i)
+
+		for k := 0; k < blocks; k++ {
+			offset := k * l.Features
+			var sumD, sumDX float64
+			for j := 0; j < l.Features; j++ {
+				g := up[offset+j]
+				dGain[j] += g * xhat[offset+j]
+				dBias[j] += g
+				dxhat[j] = g * gain[j]
+				sumD += dxhat[j]
+				sumDX += dxhat[j] * xhat[offset+j]
+			}
+
+			inv := l.lastInvStd[i*blocks+k]
+			for j := 0; j < l.Features; j++ {
+				down[offset+j] = inv / n * (n*dxhat[j] - sumD - xhat[offset+j]*sumDX)
+			}
+		}
+	}
+
+	for j := range gain {
+		gain[j] -= lr * dGain[j]
+		bias[j] -= lr * dBias[j]
+	}
+
+	return downstream
+}
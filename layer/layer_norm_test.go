@@ -0,0 +1,65 @@
+package layer
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLayerNormGradients(t *testing.T) {
+	tests := map[string]int{
+		"whole sample": 8,
+		"per token":    2,
+	}
+	for name, features := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkInputGradient(t, NewLayerNorm(features), nil, 3, 8)
+
+			l := NewLayerNorm(features)
+			checkParamGradient(t, "gain", l, l.Gain, nil, 8)
+			l = NewLayerNorm(features)
+			checkParamGradient(t, "bias", l, l.Bias, nil, 8)
+		})
+	}
+}
+
+// Statistics are taken per sample, so a sample is normalized the same way
+// alone as inside a batch, and every block comes out with zero mean and unit
+// variance.
+func TestLayerNormPerSample(t *testing.T) {
+	l := NewLayerNorm(3)
+	x := randomDense(rand.New(rand.NewPCG(1, 2)), 4, 6)
+	batch := l.Forward(x)
+
+	for i := 0; i < 4; i++ {
+		single := l.Forward(mat.NewDense(1, 6, x.RawRowView(i)))
+		if !mat.EqualApprox(single, batch.Slice(i, i+1, 0, 6), 1e-12) {
+			t.Errorf("sample %d: alone %v, in a batch %v", i, single.RawRowView(0), batch.RawRowView(i))
+		}
+
+		for k := 0; k < 2; k++ {
+			block := batch.RawRowView(i)[3*k : 3*k+3]
+			var mean, variance float64
+			for _, v := range block {
+				mean += v / 3
+			}
+			for _, v := range block {
+				variance += (v - mean) * (v - mean) / 3
+			}
+			if math.Abs(mean) > 1e-9 || math.Abs(variance-1) > 1e-3 {
+				t.Errorf("sample %d block %d: mean %v, variance %v", i, k, mean, variance)
+			}
+		}
+	}
+}
+
+func TestLayerNormWidth(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for 5 columns over 2 features")
+		}
+	}()
+	NewLayerNorm(2).Forward(mat.NewDense(1, 5, nil))
+}
@@ -0,0 +1,54 @@
+package layer
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type LeakyReLU struct {
+	Alpha float64
+
+	lastInputs *mat.Dense
+}
+
+func NewLeakyReLU(alpha float64) *LeakyReLU {
+	return &LeakyReLU{Alpha: alpha}
+}
+
+func (l *LeakyReLU) String() string {
+	return activationString(fmt.Sprintf("LeakyReLU, Alpha: %g", l.Alpha), l.lastInputs)
+}
+
+func (l *LeakyReLU) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs = mat.DenseCopyOf(inputs)
+	return l.Infer(inputs)
+}
+
+func (l *LeakyReLU) Infer(inputs *mat.Dense) *mat.Dense {
+	rows, cols := inputs.Dims()
+	out := mat.NewDense(rows, cols, nil)
+
+	out.Apply(func(_, _ int, v float64) float64 {
+		if v > 0 {
+			return v
+		}
+		return l.Alpha * v
+	}, inputs)
+
+	return out
+}
+
+func (l *LeakyReLU) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, cols := upstreamGradient.Dims()
+	downstream := mat.NewDense(rows, cols, nil)
+
+	downstream.Apply(func(r, c int, v float64) float64 {
+		if l.lastInputs.At(r, c) > 0 {
+			return v
+		}
+		return l.Alpha * v
+	}, upstreamGradient)
+
+	return downstream
+}
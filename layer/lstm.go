@@ -0,0 +1,174 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LSTM is a long short-term memory layer. The columns of Wx, Wh and Biases
+// hold the input, forget, cell and output gates in that order, Hidden
+// columns each.
+type LSTM struct {
+	Features        int
+	Hidden          int
+	ReturnSequences bool
+
+	Wx     *mat.Dense
+	Wh     *mat.Dense
+	Biases *mat.Dense
+
+	Regularization Regularization
+
+	cache *lstmCache
+}
+
+type lstmCache struct {
+	xs     []*mat.Dense
+	hs, cs []*mat.Dense
+	// gates holds the activated i, f, g, o gates of every step side by side.
+	gates []*mat.Dense
+}
+
+func NewLSTM(features, hidden int, returnSequences bool) *LSTM {
+	biases := mat.NewDense(1, 4*hidden, nil)
+	// a forget gate bias of 1 lets gradients flow through long sequences early in training
+	for j := hidden; j < 2*hidden; j++ {
+		biases.Set(0, j, 1)
+	}
+
+	return &LSTM{
+		Features:        features,
+		Hidden:          hidden,
+		ReturnSequences: returnSequences,
+		Wx:              randomInit(features, 4*hidden),
+		Wh:              randomInit(hidden, 4*hidden),
+		Biases:          biases,
+	}
+}
+
+func (l *LSTM) String() string {
+	return fmt.Sprintf("Recurrent: LSTM [%d -> %d] (Return sequences: %t)", l.Features, l.Hidden, l.ReturnSequences)
+}
+
+func (l *LSTM) run(inputs *mat.Dense) *lstmCache {
+	batchSize, cols := inputs.Dims()
+	steps := sequenceSteps("lstm", cols, l.Features)
+	h := l.Hidden
+
+	cache := &lstmCache{
+		xs:    make([]*mat.Dense, steps),
+		hs:    make([]*mat.Dense, steps+1),
+		cs:    make([]*mat.Dense, steps+1),
+		gates: make([]*mat.Dense, steps),
+	}
+	cache.hs[0] = mat.NewDense(batchSize, h, nil)
+	cache.cs[0] = mat.NewDense(batchSize, h, nil)
+
+	for t := 0; t < steps; t++ {
+		cache.xs[t] = mat.DenseCopyOf(timeStep(inputs, t, l.Features))
+
+		var z, rec mat.Dense
+		z.Mul(cache.xs[t], l.Wx)
+		rec.Mul(cache.hs[t], l.Wh)
+		z.Add(&z, &rec)
+		addRowBias(&z, l.Biases)
+		z.Apply(func(_, j int, v float64) float64 {
+			if j >= 2*h && j < 3*h {
+				return math.Tanh(v)
+			}
+			return sigmoid(v)
+		}, &z)
+		cache.gates[t] = &z
+
+		c := mat.NewDense(batchSize, h, nil)
+		hNext := mat.NewDense(batchSize, h, nil)
+		cPrev := cache.cs[t]
+		for b := 0; b < batchSize; b++ {
+			g := z.RawRowView(b)
+			for j := 0; j < h; j++ {
+				cv := g[h+j]*cPrev.At(b, j) + g[j]*g[2*h+j]
+				c.Set(b, j, cv)
+				hNext.Set(b, j, g[3*h+j]*math.Tanh(cv))
+			}
+		}
+		cache.cs[t+1] = c
+		cache.hs[t+1] = hNext
+	}
+	return cache
+}
+
+func (l *LSTM) Forward(inputs *mat.Dense) *mat.Dense {
+	l.cache = l.run(inputs)
+	return collectSteps(l.cache.hs[1:], l.ReturnSequences)
+}
+
+func (l *LSTM) Infer(inputs *mat.Dense) *mat.Dense {
+	cache := l.run(inputs)
+	return collectSteps(cache.hs[1:], l.ReturnSequences)
+}
+
+func (l *LSTM) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	steps := len(l.cache.xs)
+	batchSize, _ := l.cache.xs[0].Dims()
+	h := l.Hidden
+
+	dWx := mat.NewDense(l.Features, 4*h, nil)
+	dWh := mat.NewDense(h, 4*h, nil)
+	dB := mat.NewDense(1, 4*h, nil)
+	gradInput := mat.NewDense(batchSize, steps*l.Features, nil)
+	dhNext := mat.NewDense(batchSize, h, nil)
+	dcNext := mat.NewDense(batchSize, h, nil)
+
+	for t := steps - 1; t >= 0; t-- {
+		gates := l.cache.gates[t]
+		c, cPrev := l.cache.cs[t+1], l.cache.cs[t]
+
+		var dh mat.Dense
+		dh.Add(stepGradient(upstreamGradient, t, steps, h, l.ReturnSequences), dhNext)
+
+		// dz holds gradients w.r.t. the gate pre-activations
+		dz := mat.NewDense(batchSize, 4*h, nil)
+		dcPrev := mat.NewDense(batchSize, h, nil)
+		for b := 0; b < batchSize; b++ {
+			g := gates.RawRowView(b)
+			d := dz.RawRowView(b)
+			for j := 0; j < h; j++ {
+				i, f, gg, o := g[j], g[h+j], g[2*h+j], g[3*h+j]
+				tanhC := math.Tanh(c.At(b, j))
+				dhv := dh.At(b, j)
+
+				dc := dcNext.At(b, j) + dhv*o*(1-tanhC*tanhC)
+				d[j] = dc * gg * i * (1 - i)
+				d[h+j] = dc * cPrev.At(b, j) * f * (1 - f)
+				d[2*h+j] = dc * i * (1 - gg*gg)
+				d[3*h+j] = dhv * tanhC * o * (1 - o)
+				dcPrev.Set(b, j, dc*f)
+			}
+		}
+
+		var dWxT, dWhT mat.Dense
+		dWxT.Mul(l.cache.xs[t].T(), dz)
+		dWx.Add(dWx, &dWxT)
+		dWhT.Mul(l.cache.hs[t].T(), dz)
+		dWh.Add(dWh, &dWhT)
+		dB.Add(dB, sumRows(dz))
+
+		timeStep(gradInput, t, l.Features).Mul(dz, l.Wx.T())
+		dhNext = new(mat.Dense)
+		dhNext.Mul(dz, l.Wh.T())
+		dcNext = dcPrev
+	}
+
+	applyUpdate(l.Wx, dWx, lr)
+	applyUpdate(l.Wh, dWh, lr)
+	applyUpdate(l.Biases, dB, lr)
+	l.Regularization.apply(lr*float64(batchSize), []*mat.Dense{l.Wx, l.Wh}, []*mat.Dense{l.Biases})
+
+	return gradInput
+}
+
+func (l *LSTM) Penalty() float64 {
+	return l.Regularization.penalty([]*mat.Dense{l.Wx, l.Wh}, []*mat.Dense{l.Biases})
+}
@@ -23,6 +23,17 @@ func NewMaxPool(size, stride, inChannels, inR, inC int) *MaxPool {
 }
 
 func (l *MaxPool) Forward(inputs *mat.Dense) *mat.Dense {
+	out, maxIndices := l.pool(inputs)
+	l.maxIndices = maxIndices
+	return out
+}
+
+func (l *MaxPool) Infer(inputs *mat.Dense) *mat.Dense {
+	out, _ := l.pool(inputs)
+	return out
+}
+
+func (l *MaxPool) pool(inputs *mat.Dense) (*mat.Dense, []int) {
 	batchSize, _ := inputs.Dims()
 
 	outR := (l.InR-l.Size)/l.Stride + 1
@@ -31,7 +42,7 @@ func (l *MaxPool) Forward(inputs *mat.Dense) *mat.Dense {
 	outFeatures := l.InChannels * outR * outC
 	data := make([]float64, batchSize*outFeatures)
 
-	l.maxIndices = make([]int, batchSize*outFeatures)
+	maxIndices := make([]int, batchSize*outFeatures)
 
 	for b := 0; b < batchSize; b++ {
 		inputRow := inputs.RawRowView(b)
@@ -62,13 +73,13 @@ func (l *MaxPool) Forward(inputs *mat.Dense) *mat.Dense {
 
 					outIdx := outChannelOffset + i*outC + j
 					outputRow[outIdx] = maxVal
-					l.maxIndices[b*outFeatures+outIdx] = maxIdx
+					maxIndices[b*outFeatures+outIdx] = maxIdx
 				}
 			}
 		}
 	}
 
-	return mat.NewDense(batchSize, outFeatures, data)
+	return mat.NewDense(batchSize, outFeatures, data), maxIndices
 }
 
 func (l *MaxPool) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
@@ -0,0 +1,155 @@
+package layer
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Layer is a single step of a network, as accepted by the containers in this
+// package.
+type Layer interface {
+	Forward(inputs *mat.Dense) *mat.Dense
+	Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense
+}
+
+// Merge selects how Parallel combines the outputs of its branches.
+type Merge int
+
+const (
+	// MergeConcat places the branch outputs side by side. For channel-major
+	// outputs of equal spatial size this concatenates their channels.
+	MergeConcat Merge = iota
+	// MergeSum adds the branch outputs, which must have equal widths.
+	MergeSum
+	// MergeAverage averages the branch outputs, which must have equal widths.
+	MergeAverage
+)
+
+func (m Merge) String() string {
+	switch m {
+	case MergeConcat:
+		return "concat"
+	case MergeSum:
+		return "sum"
+	case MergeAverage:
+		return "average"
+	}
+	return fmt.Sprintf("Merge(%d)", int(m))
+}
+
+// Parallel feeds its input to every branch, a stack of layers run in order,
+// and merges the branch outputs, as in Inception-style blocks.
+type Parallel struct {
+	Branches [][]Layer
+	Merge    Merge
+
+	widths []int
+}
+
+func NewParallel(merge Merge, branches ...[]Layer) *Parallel {
+	return &Parallel{Branches: branches, Merge: merge}
+}
+
+func (l *Parallel) String() string {
+	return fmt.Sprintf("Parallel (Branches: %d, Merge: %s)", len(l.Branches), l.Merge)
+}
+
+func (l *Parallel) run(inputs *mat.Dense, step func(Layer, *mat.Dense) *mat.Dense) (*mat.Dense, []int) {
+	outputs := make([]*mat.Dense, len(l.Branches))
+	widths := make([]int, len(l.Branches))
+	for i, branch := range l.Branches {
+		out := inputs
+		for _, layer := range branch {
+			out = step(layer, out)
+		}
+		outputs[i] = out
+		_, widths[i] = out.Dims()
+	}
+
+	if l.Merge == MergeConcat {
+		return concatColumns(outputs), widths
+	}
+
+	merged := mat.DenseCopyOf(outputs[0])
+	for i, out := range outputs[1:] {
+		if widths[i+1] != widths[0] {
+			panic(fmt.Sprintf("parallel: branch %d has width %d, want %d for %s merge", i+1, widths[i+1], widths[0], l.Merge))
+		}
+		merged.Add(merged, out)
+	}
+	if l.Merge == MergeAverage {
+		merged.Scale(1/float64(len(outputs)), merged)
+	}
+	return merged, widths
+}
+
+func concatColumns(parts []*mat.Dense) *mat.Dense {
+	r, _ := parts[0].Dims()
+	width := 0
+	for _, p := range parts {
+		_, c := p.Dims()
+		width += c
+	}
+
+	out := mat.NewDense(r, width, nil)
+	offset := 0
+	for _, p := range parts {
+		_, c := p.Dims()
+		out.Slice(0, r, offset, offset+c).(*mat.Dense).Copy(p)
+		offset += c
+	}
+	return out
+}
+
+func (l *Parallel) Forward(inputs *mat.Dense) *mat.Dense {
+	out, widths := l.run(inputs, Layer.Forward)
+	l.widths = widths
+	return out
+}
+
+func (l *Parallel) Infer(inputs *mat.Dense) *mat.Dense {
+	out, _ := l.run(inputs, Infer)
+	return out
+}
+
+func (l *Parallel) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	r, _ := upstreamGradient.Dims()
+
+	var downstream *mat.Dense
+	offset := 0
+	for i, branch := range l.Branches {
+		var grad *mat.Dense
+		switch l.Merge {
+		case MergeConcat:
+			grad = mat.DenseCopyOf(upstreamGradient.Slice(0, r, offset, offset+l.widths[i]))
+			offset += l.widths[i]
+		case MergeSum:
+			grad = mat.DenseCopyOf(upstreamGradient)
+		case MergeAverage:
+			grad = mat.NewDense(r, l.widths[i], nil)
+			grad.Scale(1/float64(len(l.Branches)), upstreamGradient)
+		}
+
+		for j := len(branch) - 1; j >= 0; j-- {
+			grad = branch[j].Backward(grad, lr)
+		}
+
+		if downstream == nil {
+			downstream = grad
+		} else {
+			downstream.Add(downstream, grad)
+		}
+	}
+	return downstream
+}
+
+func (l *Parallel) Penalty() float64 {
+	total := 0.0
+	for _, branch := range l.Branches {
+		for _, layer := range branch {
+			total += PenaltyOf(layer)
+		}
+	}
+	return total
+}
@@ -0,0 +1,86 @@
+package layer
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// newParallel builds branches of width 3 for every merge, plus an identity
+// branch of width 4 when concatenating. It also returns a weight matrix
+// inside one of the branches.
+func newParallel(merge Merge) (*Parallel, *mat.Dense) {
+	inner := NewDense(4, 2)
+	branches := [][]Layer{
+		{NewDense(4, 3), NewTanh()},
+		{inner, NewSigmoid(), NewDense(2, 3)},
+	}
+	if merge == MergeConcat {
+		branches = append(branches, nil)
+	}
+	return NewParallel(merge, branches...), inner.Weights
+}
+
+func TestParallelGradients(t *testing.T) {
+	for _, merge := range []Merge{MergeConcat, MergeSum, MergeAverage} {
+		t.Run(merge.String(), func(t *testing.T) {
+			l, _ := newParallel(merge)
+			checkInputGradient(t, l, nil, 3, 4)
+
+			l, weights := newParallel(merge)
+			checkParamGradient(t, "branch weights", l, weights, nil, 4)
+		})
+	}
+}
+
+func TestParallelMerge(t *testing.T) {
+	x := randomDense(rand.New(rand.NewPCG(1, 2)), 2, 4)
+	for _, merge := range []Merge{MergeConcat, MergeSum, MergeAverage} {
+		t.Run(merge.String(), func(t *testing.T) {
+			l, _ := newParallel(merge)
+			out := l.Forward(x)
+			if !mat.Equal(out, l.Infer(x)) {
+				t.Error("Infer differs from Forward")
+			}
+
+			a := l.Branches[0][1].Forward(l.Branches[0][0].Forward(x))
+			b := l.Branches[1][2].Forward(l.Branches[1][1].Forward(l.Branches[1][0].Forward(x)))
+			var want mat.Dense
+			switch merge {
+			case MergeConcat:
+				var ab mat.Dense
+				ab.Augment(a, b)
+				want.Augment(&ab, x)
+			case MergeSum:
+				want.Add(a, b)
+			case MergeAverage:
+				want.Add(a, b)
+				want.Scale(0.5, &want)
+			}
+			if !mat.EqualApprox(out, &want, 1e-12) {
+				t.Errorf("merged\n%v\nwant\n%v", mat.Formatted(out), mat.Formatted(&want))
+			}
+		})
+	}
+}
+
+func TestParallelWidthMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for summing widths 3 and 2")
+		}
+	}()
+	NewParallel(MergeSum, []Layer{NewDense(4, 3)}, []Layer{NewDense(4, 2)}).Forward(mat.NewDense(1, 4, nil))
+}
+
+func TestParallelPenalty(t *testing.T) {
+	a, b := NewDense(2, 2), NewDense(2, 2)
+	a.Regularization = Regularization{L2: 1}
+	b.Regularization = Regularization{L1: 1}
+	l := NewParallel(MergeSum, []Layer{a}, []Layer{NewTanh(), b})
+
+	if got, want := l.Penalty(), PenaltyOf(a)+PenaltyOf(b); got != want {
+		t.Errorf("penalty = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package layer
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Permute reorders the axes of rows laid out as InShape: output axis i is
+// input axis Axes[i]. For example Axes {1, 2, 0} turns channel-first
+// [C, H, W] rows into channel-last [H, W, C] ones.
+type Permute struct {
+	InShape []int
+	Axes    []int
+
+	// sources maps every output position to the input position it is read
+	// from; it is rebuilt on first use after decoding.
+	sources []int
+}
+
+func NewPermute(inShape []int, axes ...int) *Permute {
+	if len(axes) != len(inShape) {
+		panic(fmt.Sprintf("permute: %d axes for shape %s", len(axes), shapeString(inShape)))
+	}
+	seen := make([]bool, len(axes))
+	for _, a := range axes {
+		if a < 0 || a >= len(axes) || seen[a] {
+			panic(fmt.Sprintf("permute: %v is not a permutation of %d axes", axes, len(axes)))
+		}
+		seen[a] = true
+	}
+	return &Permute{InShape: inShape, Axes: axes}
+}
+
+func (l *Permute) String() string {
+	return fmt.Sprintf("Permute %s -> %s (Axes: %v)", shapeString(l.InShape), shapeString(l.OutShape()), l.Axes)
+}
+
+func (l *Permute) OutShape() []int {
+	out := make([]int, len(l.Axes))
+	for i, a := range l.Axes {
+		out[i] = l.InShape[a]
+	}
+	return out
+}
+
+func (l *Permute) sourceIndices() []int {
+	if l.sources != nil {
+		return l.sources
+	}
+
+	n := len(l.InShape)
+	inStrides := make([]int, n)
+	stride := 1
+	for i := n - 1; i >= 0; i-- {
+		inStrides[i] = stride
+		stride *= l.InShape[i]
+	}
+
+	outShape := l.OutShape()
+	sources := make([]int, shapeSize(outShape))
+	index := make([]int, n)
+	for o := range sources {
+		src := 0
+		for i, a := range l.Axes {
+			src += index[i] * inStrides[a]
+		}
+		sources[o] = src
+
+		// advance the output index, last axis fastest
+		for i := n - 1; i >= 0; i-- {
+			index[i]++
+			if index[i] < outShape[i] {
+				break
+			}
+			index[i] = 0
+		}
+	}
+
+	l.sources = sources
+	return sources
+}
+
+func (l *Permute) Forward(inputs *mat.Dense) *mat.Dense {
+	checkWidth("permute", inputs, l.InShape)
+	sources := l.sourceIndices()
+
+	r, c := inputs.Dims()
+	out := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		in, row := inputs.RawRowView(i), out.RawRowView(i)
+		for o, src := range sources {
+			row[o] = in[src]
+		}
+	}
+	return out
+}
+
+func (l *Permute) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	checkWidth("permute", upstreamGradient, l.InShape)
+	sources := l.sourceIndices()
+
+	r, c := upstreamGradient.Dims()
+	downstream := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		up, row := upstreamGradient.RawRowView(i), downstream.RawRowView(i)
+		for o, src := range sources {
+			row[src] = up[o]
+		}
+	}
+	return downstream
+}
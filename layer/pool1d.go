@@ -0,0 +1,176 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/velosypedno/nns/im2col"
+	"gonum.org/v1/gonum/mat"
+)
+
+// pool1D calls f for every output step t of every channel with the channel's
+// offset in the output row and the input positions covered by the window of
+// t. Positions that fall on the padding are left out.
+func pool1D(size, stride, padding, dilation int, causal bool, inChannels, inLen int, f func(offset, t int, steps []int)) {
+	padLeft, _ := padding1D(size, dilation, padding, causal)
+	outLen := poolOutLen1D(size, stride, padding, dilation, causal, inLen)
+
+	steps := make([]int, 0, size)
+	for c := 0; c < inChannels; c++ {
+		for t := 0; t < outLen; t++ {
+			steps = steps[:0]
+			for k := 0; k < size; k++ {
+				src := t*stride + k*dilation - padLeft
+				if src >= 0 && src < inLen {
+					steps = append(steps, c*inLen+src)
+				}
+			}
+			f(c*outLen, t, steps)
+		}
+	}
+}
+
+// checkPadding1D panics unless padding is smaller than the dilated window,
+// which guarantees that every window covers at least one input position.
+func checkPadding1D(name string, size, padding, dilation int) {
+	if span := dilation*(size-1) + 1; padding >= span {
+		panic(fmt.Sprintf("%s: padding %d must be smaller than the window span %d", name, padding, span))
+	}
+}
+
+func poolOutLen1D(size, stride, padding, dilation int, causal bool, inLen int) int {
+	padLeft, padRight := padding1D(size, dilation, padding, causal)
+	return im2col.OutLen1D(inLen, size, stride, dilation, padLeft, padRight)
+}
+
+type MaxPool1D struct {
+	Size     int
+	Stride   int
+	Padding  int
+	Dilation int
+	Causal   bool
+
+	InChannels int
+	InLen      int
+
+	maxIndices []int
+}
+
+func NewMaxPool1D(size, stride, padding, dilation, inChannels, inLen int) *MaxPool1D {
+	checkPadding1D("max pool 1d", size, padding, dilation)
+	return &MaxPool1D{
+		Size:       size,
+		Stride:     stride,
+		Padding:    padding,
+		Dilation:   dilation,
+		InChannels: inChannels,
+		InLen:      inLen,
+	}
+}
+
+func (l *MaxPool1D) Forward(inputs *mat.Dense) *mat.Dense {
+	out, maxIndices := l.pool(inputs)
+	l.maxIndices = maxIndices
+	return out
+}
+
+func (l *MaxPool1D) Infer(inputs *mat.Dense) *mat.Dense {
+	out, _ := l.pool(inputs)
+	return out
+}
+
+func (l *MaxPool1D) pool(inputs *mat.Dense) (*mat.Dense, []int) {
+	batchSize, _ := inputs.Dims()
+	outFeatures := l.InChannels * poolOutLen1D(l.Size, l.Stride, l.Padding, l.Dilation, l.Causal, l.InLen)
+	out := mat.NewDense(batchSize, outFeatures, nil)
+	maxIndices := make([]int, batchSize*outFeatures)
+
+	for b := 0; b < batchSize; b++ {
+		in, row := inputs.RawRowView(b), out.RawRowView(b)
+		pool1D(l.Size, l.Stride, l.Padding, l.Dilation, l.Causal, l.InChannels, l.InLen, func(offset, t int, steps []int) {
+			best, bestIdx := math.Inf(-1), -1
+			for _, s := range steps {
+				if in[s] > best {
+					best, bestIdx = in[s], s
+				}
+			}
+			row[offset+t] = best
+			maxIndices[b*outFeatures+offset+t] = bestIdx
+		})
+	}
+	return out, maxIndices
+}
+
+func (l *MaxPool1D) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, outFeatures := gradOutput.Dims()
+	gradInput := mat.NewDense(batchSize, l.InChannels*l.InLen, nil)
+
+	for b := 0; b < batchSize; b++ {
+		up, down := gradOutput.RawRowView(b), gradInput.RawRowView(b)
+		for i, g := range up {
+			if idx := l.maxIndices[b*outFeatures+i]; idx >= 0 {
+				down[idx] += g
+			}
+		}
+	}
+	return gradInput
+}
+
+// AvgPool1D averages every window over the steps that fall inside the
+// sequence, so padding does not dilute the edges.
+type AvgPool1D struct {
+	Size     int
+	Stride   int
+	Padding  int
+	Dilation int
+	Causal   bool
+
+	InChannels int
+	InLen      int
+}
+
+func NewAvgPool1D(size, stride, padding, dilation, inChannels, inLen int) *AvgPool1D {
+	checkPadding1D("avg pool 1d", size, padding, dilation)
+	return &AvgPool1D{
+		Size:       size,
+		Stride:     stride,
+		Padding:    padding,
+		Dilation:   dilation,
+		InChannels: inChannels,
+		InLen:      inLen,
+	}
+}
+
+func (l *AvgPool1D) Forward(inputs *mat.Dense) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	outLen := poolOutLen1D(l.Size, l.Stride, l.Padding, l.Dilation, l.Causal, l.InLen)
+	out := mat.NewDense(batchSize, l.InChannels*outLen, nil)
+
+	for b := 0; b < batchSize; b++ {
+		in, row := inputs.RawRowView(b), out.RawRowView(b)
+		pool1D(l.Size, l.Stride, l.Padding, l.Dilation, l.Causal, l.InChannels, l.InLen, func(offset, t int, steps []int) {
+			sum := 0.0
+			for _, s := range steps {
+				sum += in[s]
+			}
+			row[offset+t] = sum / float64(len(steps))
+		})
+	}
+	return out
+}
+
+func (l *AvgPool1D) Backward(gradOutput *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := gradOutput.Dims()
+	gradInput := mat.NewDense(batchSize, l.InChannels*l.InLen, nil)
+
+	for b := 0; b < batchSize; b++ {
+		up, down := gradOutput.RawRowView(b), gradInput.RawRowView(b)
+		pool1D(l.Size, l.Stride, l.Padding, l.Dilation, l.Causal, l.InChannels, l.InLen, func(offset, t int, steps []int) {
+			g := up[offset+t] / float64(len(steps))
+			for _, s := range steps {
+				down[s] += g
+			}
+		})
+	}
+	return gradInput
+}
@@ -0,0 +1,53 @@
+package layer
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPoolGradients(t *testing.T) {
+	const channels, inR, inC = 2, 5, 5
+	tests := map[string]differentiable{
+		"avg":                   NewAvgPool(2, 1, channels, inR, inC),
+		"avg strided":           NewAvgPool(2, 2, channels, inR, inC),
+		"global avg":            NewGlobalAvgPool(channels, inR, inC),
+		"global max":            NewGlobalMaxPool(channels, inR, inC),
+		"adaptive avg":          NewAdaptiveAvgPool(2, 3, channels, inR, inC),
+		"adaptive max":          NewAdaptiveMaxPool(2, 3, channels, inR, inC),
+		"adaptive avg identity": NewAdaptiveAvgPool(inR, inC, channels, inR, inC),
+	}
+	for name, l := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkInputGradient(t, l, nil, 3, channels*inR*inC)
+		})
+	}
+}
+
+func TestAdaptivePoolOutput(t *testing.T) {
+	// one 3x3 channel reduced to 2x2: windows [0,2) and [1,3) overlap
+	x := mat.NewDense(1, 9, []float64{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 9,
+	})
+	tests := []struct {
+		name string
+		l    differentiable
+		want []float64
+	}{
+		{"avg", NewAdaptiveAvgPool(2, 2, 1, 3, 3), []float64{3, 4, 6, 7}},
+		{"max", NewAdaptiveMaxPool(2, 2, 1, 3, 3), []float64{5, 6, 8, 9}},
+		{"global avg", NewGlobalAvgPool(1, 3, 3), []float64{5}},
+		{"global max", NewGlobalMaxPool(1, 3, 3), []float64{9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.l.Forward(x).RawRowView(0)
+			if !floats.EqualApprox(got, tt.want, 1e-12) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SinusoidalPositionalEncoding adds the fixed sine/cosine position signal of
+// "Attention Is All You Need" to time-major sequences of Steps x Dim values.
+type SinusoidalPositionalEncoding struct {
+	Steps int
+	Dim   int
+}
+
+func NewSinusoidalPositionalEncoding(steps, dim int) *SinusoidalPositionalEncoding {
+	return &SinusoidalPositionalEncoding{Steps: steps, Dim: dim}
+}
+
+func (l *SinusoidalPositionalEncoding) String() string {
+	return fmt.Sprintf("Positional encoding: sinusoidal (Steps: %d, Dim: %d)", l.Steps, l.Dim)
+}
+
+func (l *SinusoidalPositionalEncoding) value(t, d int) float64 {
+	angle := float64(t) / math.Pow(10000, float64(2*(d/2))/float64(l.Dim))
+	if d%2 == 0 {
+		return math.Sin(angle)
+	}
+	return math.Cos(angle)
+}
+
+func (l *SinusoidalPositionalEncoding) Forward(inputs *mat.Dense) *mat.Dense {
+	r, c := inputs.Dims()
+	out := mat.NewDense(r, c, nil)
+	out.Apply(func(_, j int, v float64) float64 {
+		return v + l.value(j/l.Dim, j%l.Dim)
+	}, inputs)
+	return out
+}
+
+func (l *SinusoidalPositionalEncoding) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	return mat.DenseCopyOf(upstreamGradient)
+}
+
+// LearnedPositionalEncoding adds a trainable vector per position to
+// time-major sequences of Steps x Dim values.
+type LearnedPositionalEncoding struct {
+	Steps   int
+	Dim     int
+	Weights *mat.Dense
+}
+
+func NewLearnedPositionalEncoding(steps, dim int) *LearnedPositionalEncoding {
+	data := make([]float64, steps*dim)
+	for i := range data {
+		data[i] = rand.NormFloat64() * 0.02
+	}
+	// one row of Steps*Dim values lines up with the time-major input rows
+	return &LearnedPositionalEncoding{
+		Steps:   steps,
+		Dim:     dim,
+		Weights: mat.NewDense(1, steps*dim, data),
+	}
+}
+
+func (l *LearnedPositionalEncoding) String() string {
+	return fmt.Sprintf("Positional encoding: learned (Steps: %d, Dim: %d)", l.Steps, l.Dim)
+}
+
+func (l *LearnedPositionalEncoding) Forward(inputs *mat.Dense) *mat.Dense {
+	out := mat.DenseCopyOf(inputs)
+	addRowBias(out, l.Weights)
+	return out
+}
+
+func (l *LearnedPositionalEncoding) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	applyUpdate(l.Weights, sumRows(upstreamGradient), lr)
+	return mat.DenseCopyOf(upstreamGradient)
+}
@@ -0,0 +1,85 @@
+package layer
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// PReLU is a leaky ReLU with a learnable slope per channel. Input columns are
+// split into Channels contiguous blocks (channel-major layout), so for dense
+// inputs Channels equals the number of features and for conv feature maps it
+// equals the number of kernels. Like Dense, the slope gradient is summed over
+// the batch and lr is expected to be already divided by the batch size, so in
+// a CNN it belongs in the classifier layers.
+type PReLU struct {
+	Channels int
+	Alphas   *mat.Dense
+
+	lastInputs *mat.Dense
+}
+
+func NewPReLU(channels int) *PReLU {
+	alphas := make([]float64, channels)
+	for i := range alphas {
+		alphas[i] = 0.25
+	}
+	return &PReLU{
+		Channels: channels,
+		Alphas:   mat.NewDense(1, channels, alphas),
+	}
+}
+
+func (l *PReLU) String() string {
+	alphasStr := fmt.Sprintf("%v", mat.Formatted(l.Alphas, mat.Prefix("    "), mat.Squeeze()))
+	return fmt.Sprintf("Activation: PReLU (Channels: %d)\n  Alphas:\n%s", l.Channels, alphasStr)
+}
+
+func (l *PReLU) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs = mat.DenseCopyOf(inputs)
+	return l.Infer(inputs)
+}
+
+func (l *PReLU) Infer(inputs *mat.Dense) *mat.Dense {
+	rows, cols := inputs.Dims()
+	if cols%l.Channels != 0 {
+		panic(fmt.Sprintf("prelu: %d input columns are not a multiple of %d channels", cols, l.Channels))
+	}
+	channelSize := cols / l.Channels
+	alphas := l.Alphas.RawRowView(0)
+	out := mat.NewDense(rows, cols, nil)
+
+	out.Apply(func(_, c int, v float64) float64 {
+		if v > 0 {
+			return v
+		}
+		return alphas[c/channelSize] * v
+	}, inputs)
+
+	return out
+}
+
+func (l *PReLU) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, cols := upstreamGradient.Dims()
+	channelSize := cols / l.Channels
+	alphas := l.Alphas.RawRowView(0)
+
+	downstream := mat.NewDense(rows, cols, nil)
+	gradAlphas := make([]float64, l.Channels)
+
+	downstream.Apply(func(r, c int, v float64) float64 {
+		x := l.lastInputs.At(r, c)
+		if x > 0 {
+			return v
+		}
+		ch := c / channelSize
+		gradAlphas[ch] += v * x
+		return alphas[ch] * v
+	}, upstreamGradient)
+
+	for ch := range alphas {
+		alphas[ch] -= lr * gradAlphas[ch]
+	}
+
+	return downstream
+}
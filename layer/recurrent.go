@@ -0,0 +1,92 @@
+package layer
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Recurrent layers read sequences stored time-major within each row: a
+// sequence of T steps with F features occupies T*F columns as
+// [x_0 | x_1 | ... | x_{T-1}]. With ReturnSequences the output uses the same
+// layout with the hidden size in place of F; otherwise it is the last hidden
+// state only.
+//
+// Like Dense, they sum parameter gradients over the batch and expect lr to be
+// divided by the batch size already, as the MLP does.
+
+// sequenceSteps returns the number of time steps in cols input columns of
+// features each and panics when they do not divide evenly.
+func sequenceSteps(name string, cols, features int) int {
+	if cols%features != 0 {
+		panic(fmt.Sprintf("%s: %d input columns are not a multiple of %d features", name, cols, features))
+	}
+	return cols / features
+}
+
+// timeStep returns the columns of step t of a time-major batch.
+func timeStep(m *mat.Dense, t, width int) *mat.Dense {
+	r, _ := m.Dims()
+	return m.Slice(0, r, t*width, (t+1)*width).(*mat.Dense)
+}
+
+// collectSteps lays out per-step outputs in time-major order, or returns the
+// last one when returnSequences is false.
+func collectSteps(steps []*mat.Dense, returnSequences bool) *mat.Dense {
+	last := steps[len(steps)-1]
+	if !returnSequences {
+		return mat.DenseCopyOf(last)
+	}
+
+	r, width := last.Dims()
+	out := mat.NewDense(r, width*len(steps), nil)
+	for t, s := range steps {
+		timeStep(out, t, width).Copy(s)
+	}
+	return out
+}
+
+// stepGradient returns the upstream gradient for step t of T, which is zero
+// for every step but the last when only the final state was returned.
+func stepGradient(upstream *mat.Dense, t, steps, width int, returnSequences bool) *mat.Dense {
+	if returnSequences {
+		return timeStep(upstream, t, width)
+	}
+	r, _ := upstream.Dims()
+	if t == steps-1 {
+		return upstream
+	}
+	return mat.NewDense(r, width, nil)
+}
+
+// addRowBias adds the single-row bias to every row of m.
+func addRowBias(m, bias *mat.Dense) {
+	r, _ := m.Dims()
+	b := bias.RawRowView(0)
+	for i := 0; i < r; i++ {
+		row := m.RawRowView(i)
+		for j := range row {
+			row[j] += b[j]
+		}
+	}
+}
+
+// sumRows returns the column sums of m as a single-row matrix.
+func sumRows(m *mat.Dense) *mat.Dense {
+	r, c := m.Dims()
+	out := mat.NewDense(1, c, nil)
+	sums := out.RawRowView(0)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			sums[j] += m.At(i, j)
+		}
+	}
+	return out
+}
+
+// applyUpdate performs param -= lr * grad.
+func applyUpdate(param, grad *mat.Dense, lr float64) {
+	var step mat.Dense
+	step.Scale(lr, grad)
+	param.Sub(param, &step)
+}
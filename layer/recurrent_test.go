@@ -0,0 +1,79 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRecurrentGradients(t *testing.T) {
+	const features, hidden, steps = 3, 4, 5
+	// every constructor returns the layer and its Wx, Wh and Biases
+	constructors := map[string]func(returnSequences bool) (differentiable, []*mat.Dense){
+		"rnn": func(r bool) (differentiable, []*mat.Dense) {
+			l := NewRNN(features, hidden, r)
+			return l, []*mat.Dense{l.Wx, l.Wh, l.Biases}
+		},
+		"lstm": func(r bool) (differentiable, []*mat.Dense) {
+			l := NewLSTM(features, hidden, r)
+			return l, []*mat.Dense{l.Wx, l.Wh, l.Biases}
+		},
+		"gru": func(r bool) (differentiable, []*mat.Dense) {
+			l := NewGRU(features, hidden, r)
+			return l, []*mat.Dense{l.Wx, l.Wh, l.Biases}
+		},
+	}
+
+	for name, newLayer := range constructors {
+		for _, returnSequences := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s sequences=%t", name, returnSequences), func(t *testing.T) {
+				l, _ := newLayer(returnSequences)
+				checkInputGradient(t, l, nil, 2, steps*features)
+
+				for i, param := range []string{"Wx", "Wh", "Biases"} {
+					l, params := newLayer(returnSequences)
+					checkParamGradient(t, param, l, params[i], nil, steps*features)
+				}
+			})
+		}
+	}
+}
+
+func TestRecurrentOutputShape(t *testing.T) {
+	x := mat.NewDense(2, 5*3, nil)
+	tests := []struct {
+		l    differentiable
+		cols int
+	}{
+		{NewRNN(3, 4, false), 4},
+		{NewRNN(3, 4, true), 5 * 4},
+		{NewLSTM(3, 4, false), 4},
+		{NewLSTM(3, 4, true), 5 * 4},
+		{NewGRU(3, 4, false), 4},
+		{NewGRU(3, 4, true), 5 * 4},
+	}
+	for _, tt := range tests {
+		if r, c := tt.l.Forward(x).Dims(); r != 2 || c != tt.cols {
+			t.Errorf("%v: output %dx%d, want 2x%d", tt.l, r, c, tt.cols)
+		}
+	}
+}
+
+func TestRecurrentInputWidth(t *testing.T) {
+	x := mat.NewDense(2, 7, nil)
+	for name, l := range map[string]differentiable{
+		"rnn":  NewRNN(3, 4, false),
+		"lstm": NewLSTM(3, 4, false),
+		"gru":  NewGRU(3, 4, false),
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic for 7 columns of 3 features", name)
+				}
+			}()
+			l.Forward(x)
+		}()
+	}
+}
@@ -0,0 +1,102 @@
+package layer
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Regularization configures the weight penalties of a layer. L1 adds
+// L1*sum|w| and L2 adds L2*sum w^2 to the loss, while WeightDecay shrinks the
+// weights by a factor of 1 - lr*WeightDecay after every step without entering
+// the loss (decoupled weight decay). Biases are left alone unless IncludeBiases
+// is set. The zero value disables regularization.
+type Regularization struct {
+	L1            float64
+	L2            float64
+	WeightDecay   float64
+	IncludeBiases bool
+}
+
+// Penalizer is implemented by layers that add a regularization term to the
+// loss reported during training and evaluation.
+type Penalizer interface {
+	Penalty() float64
+}
+
+func (r Regularization) params(weights, biases []*mat.Dense) []*mat.Dense {
+	if !r.IncludeBiases {
+		return weights
+	}
+	return append(append([]*mat.Dense(nil), weights...), biases...)
+}
+
+// penalty returns the L1 and L2 terms for the given parameters.
+func (r Regularization) penalty(weights, biases []*mat.Dense) float64 {
+	if r.L1 == 0 && r.L2 == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, p := range r.params(weights, biases) {
+		rows, _ := p.Dims()
+		for i := 0; i < rows; i++ {
+			for _, w := range p.RawRowView(i) {
+				total += r.L1*math.Abs(w) + r.L2*w*w
+			}
+		}
+	}
+	return total
+}
+
+// apply steps the parameters against the L1 and L2 penalty gradient, then
+// decays them by a separate factor of 1 - lr*WeightDecay. lr is the step
+// size the layer applies to the gradient of the mean batch loss, so neither
+// depends on the batch size.
+func (r Regularization) apply(lr float64, weights, biases []*mat.Dense) {
+	if r.L1 == 0 && r.L2 == 0 && r.WeightDecay == 0 {
+		return
+	}
+
+	decay := 1 - lr*r.WeightDecay
+	for _, p := range r.params(weights, biases) {
+		rows, _ := p.Dims()
+		for i := 0; i < rows; i++ {
+			row := p.RawRowView(i)
+			for j, w := range row {
+				w -= lr * (r.L1*sign(w) + 2*r.L2*w)
+				row[j] = w * decay
+			}
+		}
+	}
+}
+
+// forward copies r, unless it is the zero value, into the Regularization
+// fields of a container's inner layers.
+func (r Regularization) forward(inner ...*Regularization) {
+	if r == (Regularization{}) {
+		return
+	}
+	for _, p := range inner {
+		*p = r
+	}
+}
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+// PenaltyOf returns the regularization term l adds to the loss, or zero for
+// layers without one.
+func PenaltyOf(l any) float64 {
+	if p, ok := l.(Penalizer); ok {
+		return p.Penalty()
+	}
+	return 0
+}
@@ -0,0 +1,146 @@
+package layer
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRegularizationPenalty(t *testing.T) {
+	d := NewDense(2, 2)
+	d.Weights = mat.NewDense(2, 2, []float64{1, -2, 0, 3})
+	d.Biases = mat.NewDense(1, 2, []float64{5, 5})
+
+	tests := []struct {
+		name string
+		reg  Regularization
+		want float64
+	}{
+		{"none", Regularization{}, 0},
+		{"l1", Regularization{L1: 0.5}, 3},
+		{"l2", Regularization{L2: 0.1}, 1.4},
+		{"decay only", Regularization{WeightDecay: 1}, 0},
+		{"biases", Regularization{L1: 1, IncludeBiases: true}, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d.Regularization = tt.reg
+			if got := PenaltyOf(d); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("penalty = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// With a zero loss gradient only the regularization moves the weights, by
+// lr*(L1*sign(w) + 2*L2*w) followed by the decoupled 1 - lr*WeightDecay
+// factor, whatever the batch size.
+func TestRegularizationStep(t *testing.T) {
+	const lr = 0.1
+	reg := Regularization{L1: 0.2, L2: 0.3, WeightDecay: 0.5}
+	step := func(w float64) float64 {
+		w -= lr * (reg.L1*math.Copysign(1, w) + 2*reg.L2*w)
+		return w * (1 - lr*reg.WeightDecay)
+	}
+
+	for _, batch := range []int{1, 4} {
+		d := NewDense(3, 2)
+		d.Regularization = reg
+		before := mat.DenseCopyOf(d.Weights)
+		biases := mat.DenseCopyOf(d.Biases)
+
+		d.Forward(mat.NewDense(batch, 3, nil))
+		// the MLP passes the learning rate divided by the batch size
+		d.Backward(mat.NewDense(batch, 2, nil), lr/float64(batch))
+
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 2; j++ {
+				if got, want := d.Weights.At(i, j), step(before.At(i, j)); math.Abs(got-want) > 1e-12 {
+					t.Errorf("batch %d: weight (%d,%d) = %v, want %v", batch, i, j, got, want)
+				}
+			}
+		}
+		if !mat.Equal(d.Biases, biases) {
+			t.Errorf("batch %d: biases changed without IncludeBiases", batch)
+		}
+	}
+}
+
+// The feed-forward layers of a Transformer block see one row per token, but
+// must be regularized per sample.
+func TestTransformerFeedForwardRegularizationIgnoresSteps(t *testing.T) {
+	const lr, batch = 0.1, 2
+	for _, steps := range []int{1, 5} {
+		block := NewTransformerEncoderBlock(4, 2, 8, false)
+		block.FF1.Regularization.WeightDecay = 0.5
+		before := mat.DenseCopyOf(block.FF1.Weights)
+
+		block.Forward(mat.NewDense(batch, steps*4, nil))
+		block.Backward(mat.NewDense(batch, steps*4, nil), lr/batch)
+
+		var want mat.Dense
+		want.Scale(1-lr*0.5, before)
+		if !mat.EqualApprox(block.FF1.Weights, &want, 1e-12) {
+			t.Errorf("steps %d: feed-forward weights not decayed by exactly 1 - lr*WeightDecay", steps)
+		}
+	}
+}
+
+// A container's Regularization replaces that of the layers it holds, for both
+// the reported penalty and the update.
+func TestContainerRegularization(t *testing.T) {
+	const lr = 0.1
+	reg := Regularization{L2: 0.1, WeightDecay: 0.5}
+
+	grouped := NewGroupedConv(2, 4, 2, 2, 3, 3)
+	separable := NewDepthwiseSeparableConv(2, 3, 2, 3, 3)
+	block := NewTransformerEncoderBlock(4, 2, 8, false)
+	tests := []struct {
+		name    string
+		l       differentiable
+		reg     *Regularization
+		cols    int
+		weights []*mat.Dense
+	}{
+		{"grouped conv", grouped, &grouped.Regularization, 18,
+			[]*mat.Dense{grouped.Convs[0].Kernels, grouped.Convs[1].Kernels}},
+		{"depthwise separable conv", separable, &separable.Regularization, 18,
+			[]*mat.Dense{separable.Depthwise.Convs[0].Kernels, separable.Depthwise.Convs[1].Kernels, separable.Pointwise.Kernels}},
+		{"transformer", block, &block.Regularization, 8,
+			[]*mat.Dense{block.Attention.Wqkv, block.Attention.Wo, block.FF1.Weights, block.FF2.Weights}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*tt.reg = reg
+
+			want := 0.0
+			for _, w := range tt.weights {
+				var sq mat.Dense
+				sq.MulElem(w, w)
+				want += reg.L2 * mat.Sum(&sq)
+			}
+			if got := PenaltyOf(tt.l); math.Abs(got-want) > 1e-9 {
+				t.Errorf("penalty = %v, want %v", got, want)
+			}
+
+			before := make([]*mat.Dense, len(tt.weights))
+			for i, w := range tt.weights {
+				before[i] = mat.DenseCopyOf(w)
+			}
+			// a single sample with a zero loss gradient, so only the
+			// regularization moves the weights
+			outR, outC := tt.l.Forward(mat.NewDense(1, tt.cols, nil)).Dims()
+			tt.l.Backward(mat.NewDense(outR, outC, nil), lr)
+
+			factor := (1 - lr*2*reg.L2) * (1 - lr*reg.WeightDecay)
+			for i, w := range tt.weights {
+				var wantW mat.Dense
+				wantW.Scale(factor, before[i])
+				if !mat.EqualApprox(w, &wantW, 1e-12) {
+					t.Errorf("weights %d were not regularized by the container setting", i)
+				}
+			}
+		})
+	}
+}
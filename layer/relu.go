@@ -14,7 +14,10 @@ func NewReLU() *ReLU {
 
 func (l *ReLU) Forward(inputs *mat.Dense) *mat.Dense {
 	l.lastInputs = mat.DenseCopyOf(inputs)
+	return l.Infer(inputs)
+}
 
+func (l *ReLU) Infer(inputs *mat.Dense) *mat.Dense {
 	r, c := inputs.Dims()
 	data := inputs.RawMatrix().Data
 	outputData := make([]float64, len(data))
@@ -0,0 +1,90 @@
+package layer
+
+import (
+	"fmt"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Rows are always flat; the shape layers below only record how the values of
+// a row are laid out, outermost axis first, e.g. [channels, rows, cols] for
+// the channel-major images read by Conv. Forward and Backward check that
+// every row has exactly as many values as the recorded shape.
+
+func shapeSize(shape []int) int {
+	size := 1
+	for _, d := range shape {
+		size *= d
+	}
+	return size
+}
+
+func shapeString(shape []int) string {
+	dims := make([]string, len(shape))
+	for i, d := range shape {
+		dims[i] = fmt.Sprint(d)
+	}
+	return "[" + strings.Join(dims, " x ") + "]"
+}
+
+func checkWidth(name string, m *mat.Dense, shape []int) {
+	if _, c := m.Dims(); c != shapeSize(shape) {
+		panic(fmt.Sprintf("%s: got %d columns, want %d for shape %s", name, c, shapeSize(shape), shapeString(shape)))
+	}
+}
+
+// Flatten marks the switch from a shaped layout, such as the channel-major
+// output of a Conv stack, to a plain feature vector.
+type Flatten struct {
+	InShape []int
+}
+
+func NewFlatten(inShape ...int) *Flatten {
+	return &Flatten{InShape: inShape}
+}
+
+func (l *Flatten) String() string {
+	return fmt.Sprintf("Flatten %s -> [%d]", shapeString(l.InShape), shapeSize(l.InShape))
+}
+
+func (l *Flatten) OutShape() []int {
+	return []int{shapeSize(l.InShape)}
+}
+
+func (l *Flatten) Forward(inputs *mat.Dense) *mat.Dense {
+	checkWidth("flatten", inputs, l.InShape)
+	return mat.DenseCopyOf(inputs)
+}
+
+func (l *Flatten) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	checkWidth("flatten", upstreamGradient, l.OutShape())
+	return mat.DenseCopyOf(upstreamGradient)
+}
+
+// Reshape reinterprets rows of InShape as OutShape without moving any value.
+type Reshape struct {
+	InShape  []int
+	OutShape []int
+}
+
+func NewReshape(inShape, outShape []int) *Reshape {
+	if shapeSize(inShape) != shapeSize(outShape) {
+		panic(fmt.Sprintf("reshape: cannot reshape %s into %s", shapeString(inShape), shapeString(outShape)))
+	}
+	return &Reshape{InShape: inShape, OutShape: outShape}
+}
+
+func (l *Reshape) String() string {
+	return fmt.Sprintf("Reshape %s -> %s", shapeString(l.InShape), shapeString(l.OutShape))
+}
+
+func (l *Reshape) Forward(inputs *mat.Dense) *mat.Dense {
+	checkWidth("reshape", inputs, l.InShape)
+	return mat.DenseCopyOf(inputs)
+}
+
+func (l *Reshape) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	checkWidth("reshape", upstreamGradient, l.OutShape)
+	return mat.DenseCopyOf(upstreamGradient)
+}
@@ -0,0 +1,81 @@
+package layer
+
+import (
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestShapeLayerGradients(t *testing.T) {
+	tests := map[string]differentiable{
+		"flatten":      NewFlatten(2, 2, 3),
+		"reshape":      NewReshape([]int{2, 6}, []int{3, 4}),
+		"permute":      NewPermute([]int{2, 2, 3}, 1, 2, 0),
+		"permute swap": NewPermute([]int{3, 4}, 1, 0),
+	}
+	for name, l := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkInputGradient(t, l, nil, 2, 12)
+		})
+	}
+}
+
+func TestPermute(t *testing.T) {
+	// two channels of a 2x3 image, channel first
+	x := mat.NewDense(1, 12, []float64{
+		0, 1, 2,
+		3, 4, 5,
+
+		10, 11, 12,
+		13, 14, 15,
+	})
+
+	tests := []struct {
+		axes  []int
+		shape []int
+		want  []float64
+	}{
+		// channel last: every pixel's two channels become adjacent
+		{[]int{1, 2, 0}, []int{2, 3, 2}, []float64{0, 10, 1, 11, 2, 12, 3, 13, 4, 14, 5, 15}},
+		// transpose each channel
+		{[]int{0, 2, 1}, []int{2, 3, 2}, []float64{0, 3, 1, 4, 2, 5, 10, 13, 11, 14, 12, 15}},
+		{[]int{0, 1, 2}, []int{2, 2, 3}, x.RawRowView(0)},
+	}
+	for _, tt := range tests {
+		l := NewPermute([]int{2, 2, 3}, tt.axes...)
+		if got := l.OutShape(); !slices.Equal(got, tt.shape) {
+			t.Errorf("axes %v: shape %v, want %v", tt.axes, got, tt.shape)
+		}
+		if got := l.Forward(x).RawRowView(0); !slices.Equal(got, tt.want) {
+			t.Errorf("axes %v: got %v, want %v", tt.axes, got, tt.want)
+		}
+	}
+}
+
+func TestShapeValidation(t *testing.T) {
+	tests := map[string]func(){
+		"reshape size":       func() { NewReshape([]int{2, 3}, []int{4, 2}) },
+		"permute axis count": func() { NewPermute([]int{2, 3}, 0) },
+		"permute repeated":   func() { NewPermute([]int{2, 3}, 1, 1) },
+		"permute range":      func() { NewPermute([]int{2, 3}, 0, 2) },
+		"flatten width":      func() { NewFlatten(2, 3).Forward(mat.NewDense(1, 5, nil)) },
+		"reshape width":      func() { NewReshape([]int{6}, []int{2, 3}).Forward(mat.NewDense(1, 7, nil)) },
+		"permute width":      func() { NewPermute([]int{2, 3}, 1, 0).Forward(mat.NewDense(1, 5, nil)) },
+		"backward width": func() {
+			l := NewFlatten(2, 3)
+			l.Forward(mat.NewDense(1, 6, nil))
+			l.Backward(mat.NewDense(1, 4, nil), 0)
+		},
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// RNN is an Elman recurrent layer: h_t = tanh(x_t*Wx + h_{t-1}*Wh + b).
+type RNN struct {
+	Features        int
+	Hidden          int
+	ReturnSequences bool
+
+	Wx     *mat.Dense
+	Wh     *mat.Dense
+	Biases *mat.Dense
+
+	Regularization Regularization
+
+	lastInputs []*mat.Dense
+	lastStates []*mat.Dense
+}
+
+func NewRNN(features, hidden int, returnSequences bool) *RNN {
+	return &RNN{
+		Features:        features,
+		Hidden:          hidden,
+		ReturnSequences: returnSequences,
+		Wx:              randomInit(features, hidden),
+		Wh:              randomInit(hidden, hidden),
+		Biases:          mat.NewDense(1, hidden, nil),
+	}
+}
+
+func (l *RNN) String() string {
+	return fmt.Sprintf("Recurrent: RNN [%d -> %d] (Return sequences: %t)", l.Features, l.Hidden, l.ReturnSequences)
+}
+
+// run returns the input of every step and the hidden states h_{-1}..h_{T-1}.
+func (l *RNN) run(inputs *mat.Dense) ([]*mat.Dense, []*mat.Dense) {
+	batchSize, cols := inputs.Dims()
+	steps := sequenceSteps("rnn", cols, l.Features)
+
+	xs := make([]*mat.Dense, steps)
+	hs := make([]*mat.Dense, steps+1)
+	hs[0] = mat.NewDense(batchSize, l.Hidden, nil)
+
+	for t := 0; t < steps; t++ {
+		xs[t] = mat.DenseCopyOf(timeStep(inputs, t, l.Features))
+
+		var a, rec mat.Dense
+		a.Mul(xs[t], l.Wx)
+		rec.Mul(hs[t], l.Wh)
+		a.Add(&a, &rec)
+		addRowBias(&a, l.Biases)
+		a.Apply(func(_, _ int, v float64) float64 {
+			return math.Tanh(v)
+		}, &a)
+		hs[t+1] = &a
+	}
+	return xs, hs
+}
+
+func (l *RNN) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs, l.lastStates = l.run(inputs)
+	return collectSteps(l.lastStates[1:], l.ReturnSequences)
+}
+
+func (l *RNN) Infer(inputs *mat.Dense) *mat.Dense {
+	_, hs := l.run(inputs)
+	return collectSteps(hs[1:], l.ReturnSequences)
+}
+
+func (l *RNN) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	steps := len(l.lastInputs)
+	batchSize, _ := l.lastInputs[0].Dims()
+
+	dWx := mat.NewDense(l.Features, l.Hidden, nil)
+	dWh := mat.NewDense(l.Hidden, l.Hidden, nil)
+	dB := mat.NewDense(1, l.Hidden, nil)
+	gradInput := mat.NewDense(batchSize, steps*l.Features, nil)
+	dhNext := mat.NewDense(batchSize, l.Hidden, nil)
+
+	for t := steps - 1; t >= 0; t-- {
+		h := l.lastStates[t+1]
+
+		var da mat.Dense
+		da.Add(stepGradient(upstreamGradient, t, steps, l.Hidden, l.ReturnSequences), dhNext)
+		da.Apply(func(i, j int, v float64) float64 {
+			hv := h.At(i, j)
+			return v * (1 - hv*hv)
+		}, &da)
+
+		var dWxT, dWhT mat.Dense
+		dWxT.Mul(l.lastInputs[t].T(), &da)
+		dWx.Add(dWx, &dWxT)
+		dWhT.Mul(l.lastStates[t].T(), &da)
+		dWh.Add(dWh, &dWhT)
+		dB.Add(dB, sumRows(&da))
+
+		timeStep(gradInput, t, l.Features).Mul(&da, l.Wx.T())
+		dhNext = new(mat.Dense)
+		dhNext.Mul(&da, l.Wh.T())
+	}
+
+	applyUpdate(l.Wx, dWx, lr)
+	applyUpdate(l.Wh, dWh, lr)
+	applyUpdate(l.Biases, dB, lr)
+	l.Regularization.apply(lr*float64(batchSize), []*mat.Dense{l.Wx, l.Wh}, []*mat.Dense{l.Biases})
+
+	return gradInput
+}
+
+func (l *RNN) Penalty() float64 {
+	return l.Regularization.penalty([]*mat.Dense{l.Wx, l.Wh}, []*mat.Dense{l.Biases})
+}
@@ -0,0 +1,66 @@
+package layer
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	seluAlpha = 1.6732632423543772848170429916717
+	seluScale = 1.0507009873554804934193349852946
+)
+
+type SELU struct {
+	lastInputs *mat.Dense
+}
+
+func NewSELU() *SELU {
+	return &SELU{}
+}
+
+func (l *SELU) String() string {
+	return activationString("SELU", l.lastInputs)
+}
+
+func (l *SELU) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs = mat.DenseCopyOf(inputs)
+	return l.Infer(inputs)
+}
+
+func (l *SELU) Infer(inputs *mat.Dense) *mat.Dense {
+	rows, cols := inputs.Dims()
+	out := mat.NewDense(rows, cols, nil)
+
+	out.Apply(func(_, _ int, v float64) float64 {
+		if v > 0 {
+			return seluScale * v
+		}
+		return seluScale * seluAlpha * math.Expm1(v)
+	}, inputs)
+
+	return out
+}
+
+func (l *SELU) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, cols := upstreamGradient.Dims()
+	downstream := mat.NewDense(rows, cols, nil)
+
+	downstream.Apply(func(r, c int, v float64) float64 {
+		x := l.lastInputs.At(r, c)
+		if x > 0 {
+			return v * seluScale
+		}
+		return v * seluScale * seluAlpha * math.Exp(x)
+	}, upstreamGradient)
+
+	return downstream
+}
+
+func (l *SELU) GobEncode() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (l *SELU) GobDecode(data []byte) error {
+	return nil
+}
@@ -0,0 +1,64 @@
+package layer
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Sigmoid struct {
+	lastOutputs *mat.Dense
+}
+
+func NewSigmoid() *Sigmoid {
+	return &Sigmoid{}
+}
+
+func sigmoid(x float64) float64 {
+	if x >= 0 {
+		return 1 / (1 + math.Exp(-x))
+	}
+	e := math.Exp(x)
+	return e / (1 + e)
+}
+
+func (l *Sigmoid) String() string {
+	return activationString("Sigmoid", l.lastOutputs)
+}
+
+func (l *Sigmoid) Forward(inputs *mat.Dense) *mat.Dense {
+	out := l.Infer(inputs)
+	l.lastOutputs = out
+	return out
+}
+
+func (l *Sigmoid) Infer(inputs *mat.Dense) *mat.Dense {
+	rows, cols := inputs.Dims()
+	out := mat.NewDense(rows, cols, nil)
+
+	out.Apply(func(_, _ int, v float64) float64 {
+		return sigmoid(v)
+	}, inputs)
+
+	return out
+}
+
+func (l *Sigmoid) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, cols := upstreamGradient.Dims()
+	downstream := mat.NewDense(rows, cols, nil)
+
+	downstream.Apply(func(r, c int, v float64) float64 {
+		out := l.lastOutputs.At(r, c)
+		return v * out * (1 - out)
+	}, upstreamGradient)
+
+	return downstream
+}
+
+func (l *Sigmoid) GobEncode() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (l *Sigmoid) GobDecode(data []byte) error {
+	return nil
+}
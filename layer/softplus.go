@@ -0,0 +1,58 @@
+package layer
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Softplus struct {
+	lastInputs *mat.Dense
+}
+
+func NewSoftplus() *Softplus {
+	return &Softplus{}
+}
+
+func (l *Softplus) String() string {
+	return activationString("Softplus", l.lastInputs)
+}
+
+func (l *Softplus) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs = mat.DenseCopyOf(inputs)
+	return l.Infer(inputs)
+}
+
+func (l *Softplus) Infer(inputs *mat.Dense) *mat.Dense {
+	rows, cols := inputs.Dims()
+	out := mat.NewDense(rows, cols, nil)
+
+	// log(1 + e^x) rewritten to avoid overflow for large x
+	out.Apply(func(_, _ int, v float64) float64 {
+		if v > 0 {
+			return v + math.Log1p(math.Exp(-v))
+		}
+		return math.Log1p(math.Exp(v))
+	}, inputs)
+
+	return out
+}
+
+func (l *Softplus) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, cols := upstreamGradient.Dims()
+	downstream := mat.NewDense(rows, cols, nil)
+
+	downstream.Apply(func(r, c int, v float64) float64 {
+		return v * sigmoid(l.lastInputs.At(r, c))
+	}, upstreamGradient)
+
+	return downstream
+}
+
+func (l *Softplus) GobEncode() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (l *Softplus) GobDecode(data []byte) error {
+	return nil
+}
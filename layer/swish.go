@@ -0,0 +1,55 @@
+package layer
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Swish computes x * sigmoid(Beta * x). With Beta = 1 it is SiLU.
+type Swish struct {
+	Beta float64
+
+	lastInputs *mat.Dense
+}
+
+func NewSwish(beta float64) *Swish {
+	return &Swish{Beta: beta}
+}
+
+func NewSiLU() *Swish {
+	return &Swish{Beta: 1}
+}
+
+func (l *Swish) String() string {
+	return activationString(fmt.Sprintf("Swish, Beta: %g", l.Beta), l.lastInputs)
+}
+
+func (l *Swish) Forward(inputs *mat.Dense) *mat.Dense {
+	l.lastInputs = mat.DenseCopyOf(inputs)
+	return l.Infer(inputs)
+}
+
+func (l *Swish) Infer(inputs *mat.Dense) *mat.Dense {
+	rows, cols := inputs.Dims()
+	out := mat.NewDense(rows, cols, nil)
+
+	out.Apply(func(_, _ int, v float64) float64 {
+		return v * sigmoid(l.Beta*v)
+	}, inputs)
+
+	return out
+}
+
+func (l *Swish) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	rows, cols := upstreamGradient.Dims()
+	downstream := mat.NewDense(rows, cols, nil)
+
+	downstream.Apply(func(r, c int, v float64) float64 {
+		x := l.lastInputs.At(r, c)
+		s := sigmoid(l.Beta * x)
+		return v * (s + l.Beta*x*s*(1-s))
+	}, upstreamGradient)
+
+	return downstream
+}
@@ -24,6 +24,12 @@ func (l *Tanh) String() string {
 }
 
 func (l *Tanh) Forward(inputs *mat.Dense) *mat.Dense {
+	out := l.Infer(inputs)
+	l.LastOutputs = out
+	return out
+}
+
+func (l *Tanh) Infer(inputs *mat.Dense) *mat.Dense {
 	rows, cols := inputs.Dims()
 	out := mat.NewDense(rows, cols, nil)
 
@@ -31,7 +37,6 @@ func (l *Tanh) Forward(inputs *mat.Dense) *mat.Dense {
 		return math.Tanh(v)
 	}, inputs)
 
-	l.LastOutputs = out
 	return out
 }
 
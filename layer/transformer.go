@@ -0,0 +1,104 @@
+package layer
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// TransformerEncoderBlock is a pre-norm Transformer encoder layer over
+// time-major sequences of Dim-sized tokens:
+//
+//	a = x + Attention(Norm1(x))
+//	y = a + FF2(GELU(FF1(Norm2(a))))
+//
+// The feed-forward network is applied to every token independently. All
+// sublayers sum their parameter gradients over the batch, so the block is
+// trained with the learning rate already divided by the batch size, as in
+// an MLP.
+type TransformerEncoderBlock struct {
+	Dim    int
+	Hidden int
+
+	Norm1      *LayerNorm
+	Attention  *MultiHeadAttention
+	Norm2      *LayerNorm
+	FF1        *Dense
+	Activation *GELU
+	FF2        *Dense
+
+	// Regularization, when not the zero value, replaces the settings of the
+	// attention and feed-forward layers.
+	Regularization Regularization
+}
+
+func NewTransformerEncoderBlock(dim, heads, hidden int, causal bool) *TransformerEncoderBlock {
+	return &TransformerEncoderBlock{
+		Dim:        dim,
+		Hidden:     hidden,
+		Norm1:      NewLayerNorm(dim),
+		Attention:  NewMultiHeadAttention(dim, heads, causal),
+		Norm2:      NewLayerNorm(dim),
+		FF1:        NewDense(dim, hidden),
+		Activation: NewGELU(),
+		FF2:        NewDense(hidden, dim),
+	}
+}
+
+func (l *TransformerEncoderBlock) String() string {
+	return fmt.Sprintf("Transformer encoder (Dim: %d, Heads: %d, Hidden: %d)", l.Dim, l.Attention.Heads, l.Hidden)
+}
+
+func (l *TransformerEncoderBlock) Forward(inputs *mat.Dense) *mat.Dense {
+	batch, _ := inputs.Dims()
+
+	var a mat.Dense
+	a.Add(inputs, l.Attention.Forward(l.Norm1.Forward(inputs)))
+
+	ff := toTokens(l.Norm2.Forward(&a), l.Dim)
+	ff = l.FF2.Forward(l.Activation.Forward(l.FF1.Forward(ff)))
+
+	var out mat.Dense
+	out.Add(&a, fromTokens(ff, batch))
+	return &out
+}
+
+func (l *TransformerEncoderBlock) Infer(inputs *mat.Dense) *mat.Dense {
+	batch, _ := inputs.Dims()
+
+	var a mat.Dense
+	a.Add(inputs, l.Attention.Infer(l.Norm1.Infer(inputs)))
+
+	ff := toTokens(l.Norm2.Infer(&a), l.Dim)
+	ff = l.FF2.Infer(l.Activation.Infer(l.FF1.Infer(ff)))
+
+	var out mat.Dense
+	out.Add(&a, fromTokens(ff, batch))
+	return &out
+}
+
+func (l *TransformerEncoderBlock) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	batch, _ := upstreamGradient.Dims()
+	l.regularize()
+
+	grad := toTokens(upstreamGradient, l.Dim)
+	// the feed-forward layers see one row per token but scale their bias step
+	// and regularization by the number of samples
+	grad = l.FF1.backward(l.Activation.Backward(l.FF2.backward(grad, lr, batch), lr), lr, batch)
+
+	var da mat.Dense
+	da.Add(upstreamGradient, l.Norm2.Backward(fromTokens(grad, batch), lr))
+
+	var dx mat.Dense
+	dx.Add(&da, l.Norm1.Backward(l.Attention.Backward(&da, lr), lr))
+	return &dx
+}
+
+func (l *TransformerEncoderBlock) regularize() {
+	l.Regularization.forward(&l.Attention.Regularization, &l.FF1.Regularization, &l.FF2.Regularization)
+}
+
+func (l *TransformerEncoderBlock) Penalty() float64 {
+	l.regularize()
+	return l.Attention.Penalty() + l.FF1.Penalty() + l.FF2.Penalty()
+}
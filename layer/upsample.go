@@ -0,0 +1,138 @@
+package layer
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// NearestUpsample enlarges every channel of a channel-major input Scale times
+// in both directions by repeating each pixel.
+type NearestUpsample struct {
+	Scale      int
+	InChannels int
+	InR, InC   int
+}
+
+func NewNearestUpsample(scale, inChannels, inR, inC int) *NearestUpsample {
+	return &NearestUpsample{Scale: scale, InChannels: inChannels, InR: inR, InC: inC}
+}
+
+func (l *NearestUpsample) Forward(inputs *mat.Dense) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	outR, outC := l.InR*l.Scale, l.InC*l.Scale
+	out := mat.NewDense(batchSize, l.InChannels*outR*outC, nil)
+
+	for b := 0; b < batchSize; b++ {
+		in, row := inputs.RawRowView(b), out.RawRowView(b)
+		for c := 0; c < l.InChannels; c++ {
+			for y := 0; y < outR; y++ {
+				for x := 0; x < outC; x++ {
+					row[c*outR*outC+y*outC+x] = in[c*l.InR*l.InC+(y/l.Scale)*l.InC+x/l.Scale]
+				}
+			}
+		}
+	}
+	return out
+}
+
+func (l *NearestUpsample) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := upstreamGradient.Dims()
+	outR, outC := l.InR*l.Scale, l.InC*l.Scale
+	downstream := mat.NewDense(batchSize, l.InChannels*l.InR*l.InC, nil)
+
+	for b := 0; b < batchSize; b++ {
+		up, row := upstreamGradient.RawRowView(b), downstream.RawRowView(b)
+		for c := 0; c < l.InChannels; c++ {
+			for y := 0; y < outR; y++ {
+				for x := 0; x < outC; x++ {
+					row[c*l.InR*l.InC+(y/l.Scale)*l.InC+x/l.Scale] += up[c*outR*outC+y*outC+x]
+				}
+			}
+		}
+	}
+	return downstream
+}
+
+// BilinearUpsample enlarges every channel of a channel-major input Scale
+// times in both directions with bilinear interpolation between pixel
+// centres, clamping at the borders.
+type BilinearUpsample struct {
+	Scale      int
+	InChannels int
+	InR, InC   int
+}
+
+func NewBilinearUpsample(scale, inChannels, inR, inC int) *BilinearUpsample {
+	return &BilinearUpsample{Scale: scale, InChannels: inChannels, InR: inR, InC: inC}
+}
+
+// bilinearTap is one of the two source positions an output coordinate
+// interpolates between along an axis, with the weight of the lower one.
+type bilinearTap struct {
+	lo, hi int
+	w      float64
+}
+
+func bilinearTaps(out, in, scale int) []bilinearTap {
+	taps := make([]bilinearTap, out)
+	for i := range taps {
+		src := math.Max((float64(i)+0.5)/float64(scale)-0.5, 0)
+		lo := int(src)
+		if lo > in-1 {
+			lo = in - 1
+		}
+		hi := lo + 1
+		if hi > in-1 {
+			hi = in - 1
+		}
+		taps[i] = bilinearTap{lo: lo, hi: hi, w: 1 - (src - float64(lo))}
+	}
+	return taps
+}
+
+// visit calls f for the four weighted source pixels of every output pixel.
+func (l *BilinearUpsample) visit(f func(src, dst int, w float64)) {
+	outR, outC := l.InR*l.Scale, l.InC*l.Scale
+	rows := bilinearTaps(outR, l.InR, l.Scale)
+	cols := bilinearTaps(outC, l.InC, l.Scale)
+
+	for c := 0; c < l.InChannels; c++ {
+		inOffset, outOffset := c*l.InR*l.InC, c*outR*outC
+		for y, ry := range rows {
+			for x, rx := range cols {
+				dst := outOffset + y*outC + x
+				f(inOffset+ry.lo*l.InC+rx.lo, dst, ry.w*rx.w)
+				f(inOffset+ry.lo*l.InC+rx.hi, dst, ry.w*(1-rx.w))
+				f(inOffset+ry.hi*l.InC+rx.lo, dst, (1-ry.w)*rx.w)
+				f(inOffset+ry.hi*l.InC+rx.hi, dst, (1-ry.w)*(1-rx.w))
+			}
+		}
+	}
+}
+
+func (l *BilinearUpsample) Forward(inputs *mat.Dense) *mat.Dense {
+	batchSize, _ := inputs.Dims()
+	out := mat.NewDense(batchSize, l.InChannels*l.InR*l.Scale*l.InC*l.Scale, nil)
+
+	for b := 0; b < batchSize; b++ {
+		in, row := inputs.RawRowView(b), out.RawRowView(b)
+		l.visit(func(src, dst int, w float64) {
+			row[dst] += w * in[src]
+		})
+	}
+	return out
+}
+
+func (l *BilinearUpsample) Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense {
+	batchSize, _ := upstreamGradient.Dims()
+	downstream := mat.NewDense(batchSize, l.InChannels*l.InR*l.InC, nil)
+
+	for b := 0; b < batchSize; b++ {
+		up, row := upstreamGradient.RawRowView(b), downstream.RawRowView(b)
+		l.visit(func(src, dst int, w float64) {
+			row[src] += w * up[dst]
+		})
+	}
+	return downstream
+}
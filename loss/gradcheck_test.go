@@ -0,0 +1,50 @@
+package loss
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type differentiable interface {
+	Calculate(output, target *mat.Dense) float64
+	Derivative(output, target *mat.Dense) *mat.Dense
+}
+
+func randomDense(rng *rand.Rand, r, c int) *mat.Dense {
+	m := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, rng.NormFloat64())
+		}
+	}
+	return m
+}
+
+// checkDerivative compares f.Derivative with central differences of
+// f.Calculate. Calculate averages while Derivative does not, so the numeric
+// gradient is multiplied by norm, the count Calculate divides by.
+func checkDerivative(t *testing.T, f differentiable, output, target *mat.Dense, norm int) {
+	t.Helper()
+	const h = 1e-6
+	grad := f.Derivative(output, target)
+
+	r, c := output.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			orig := output.At(i, j)
+			output.Set(i, j, orig+h)
+			plus := f.Calculate(output, target)
+			output.Set(i, j, orig-h)
+			minus := f.Calculate(output, target)
+			output.Set(i, j, orig)
+
+			numeric := (plus - minus) / (2 * h) * float64(norm)
+			if got := grad.At(i, j); math.Abs(got-numeric) > 1e-6*math.Max(1, math.Abs(numeric)) {
+				t.Errorf("derivative (%d,%d) = %v, numeric %v", i, j, got, numeric)
+			}
+		}
+	}
+}
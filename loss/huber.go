@@ -0,0 +1,50 @@
+package loss
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Huber is quadratic for residuals up to Delta and linear beyond it, so
+// outliers contribute a bounded gradient.
+type Huber struct {
+	Delta float64
+}
+
+func NewHuber(delta float64) *Huber {
+	return &Huber{Delta: delta}
+}
+
+func (f *Huber) Calculate(output, target *mat.Dense) float64 {
+	var diff mat.Dense
+	diff.Sub(output, target)
+
+	r, c := diff.Dims()
+	sum := 0.0
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := math.Abs(diff.At(i, j))
+			if v <= f.Delta {
+				sum += 0.5 * v * v
+			} else {
+				sum += f.Delta * (v - 0.5*f.Delta)
+			}
+		}
+	}
+	return sum / float64(r*c)
+}
+
+func (f *Huber) Derivative(output, target *mat.Dense) *mat.Dense {
+	r, c := output.Dims()
+	currentGradient := mat.NewDense(r, c, nil)
+	currentGradient.Sub(output, target)
+	currentGradient.Apply(func(_, _ int, v float64) float64 {
+		return math.Max(-f.Delta, math.Min(f.Delta, v))
+	}, currentGradient)
+	return currentGradient
+}
+
+func (*Huber) Transform(output *mat.Dense) *mat.Dense {
+	return output
+}
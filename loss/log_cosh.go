@@ -0,0 +1,43 @@
+package loss
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type LogCosh struct{}
+
+func NewLogCosh() *LogCosh {
+	return &LogCosh{}
+}
+
+func (*LogCosh) Calculate(output, target *mat.Dense) float64 {
+	var diff mat.Dense
+	diff.Sub(output, target)
+
+	r, c := diff.Dims()
+	sum := 0.0
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			// log(cosh(x)) = |x| + log(1 + e^(-2|x|)) - log(2), stable for large |x|
+			v := math.Abs(diff.At(i, j))
+			sum += v + math.Log1p(math.Exp(-2*v)) - math.Ln2
+		}
+	}
+	return sum / float64(r*c)
+}
+
+func (*LogCosh) Derivative(output, target *mat.Dense) *mat.Dense {
+	r, c := output.Dims()
+	currentGradient := mat.NewDense(r, c, nil)
+	currentGradient.Sub(output, target)
+	currentGradient.Apply(func(_, _ int, v float64) float64 {
+		return math.Tanh(v)
+	}, currentGradient)
+	return currentGradient
+}
+
+func (*LogCosh) Transform(output *mat.Dense) *mat.Dense {
+	return output
+}
@@ -0,0 +1,48 @@
+package loss
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type MAE struct{}
+
+func NewMAE() *MAE {
+	return &MAE{}
+}
+
+func (*MAE) Calculate(output, target *mat.Dense) float64 {
+	var diff mat.Dense
+	diff.Sub(output, target)
+
+	r, c := diff.Dims()
+	sum := 0.0
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			sum += math.Abs(diff.At(i, j))
+		}
+	}
+	return sum / float64(r*c)
+}
+
+func (*MAE) Derivative(output, target *mat.Dense) *mat.Dense {
+	r, c := output.Dims()
+	currentGradient := mat.NewDense(r, c, nil)
+	currentGradient.Sub(output, target)
+	currentGradient.Apply(func(_, _ int, v float64) float64 {
+		switch {
+		case v > 0:
+			return 1
+		case v < 0:
+			return -1
+		default:
+			return 0
+		}
+	}, currentGradient)
+	return currentGradient
+}
+
+func (*MAE) Transform(output *mat.Dense) *mat.Dense {
+	return output
+}
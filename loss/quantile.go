@@ -0,0 +1,49 @@
+package loss
+
+import "gonum.org/v1/gonum/mat"
+
+// Quantile is the pinball loss. Minimizing it makes the output estimate the
+// Q-th quantile of the target distribution, with Q in (0, 1).
+type Quantile struct {
+	Q float64
+}
+
+func NewQuantile(q float64) *Quantile {
+	return &Quantile{Q: q}
+}
+
+func (f *Quantile) Calculate(output, target *mat.Dense) float64 {
+	var diff mat.Dense
+	diff.Sub(target, output)
+
+	r, c := diff.Dims()
+	sum := 0.0
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := diff.At(i, j)
+			if v >= 0 {
+				sum += f.Q * v
+			} else {
+				sum += (f.Q - 1) * v
+			}
+		}
+	}
+	return sum / float64(r*c)
+}
+
+func (f *Quantile) Derivative(output, target *mat.Dense) *mat.Dense {
+	r, c := output.Dims()
+	currentGradient := mat.NewDense(r, c, nil)
+	currentGradient.Sub(target, output)
+	currentGradient.Apply(func(_, _ int, v float64) float64 {
+		if v > 0 {
+			return -f.Q
+		}
+		return 1 - f.Q
+	}, currentGradient)
+	return currentGradient
+}
+
+func (*Quantile) Transform(output *mat.Dense) *mat.Dense {
+	return output
+}
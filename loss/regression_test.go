@@ -0,0 +1,60 @@
+package loss
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRegressionLossDerivatives(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	output := randomDense(rng, 4, 3)
+	target := randomDense(rng, 4, 3)
+
+	tests := map[string]differentiable{
+		"mae":      NewMAE(),
+		"huber":    NewHuber(0.5),
+		"log cosh": NewLogCosh(),
+		"quantile": NewQuantile(0.8),
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkDerivative(t, f, output, target, 4*3)
+		})
+	}
+}
+
+func TestRegressionLossCalculate(t *testing.T) {
+	// residuals output - target of 0.5 and -3
+	output := mat.NewDense(1, 2, []float64{1.5, 0})
+	target := mat.NewDense(1, 2, []float64{1, 3})
+
+	tests := []struct {
+		name string
+		f    differentiable
+		want float64
+	}{
+		{"mae", NewMAE(), (0.5 + 3) / 2},
+		{"huber", NewHuber(1), (0.5*0.25 + (3 - 0.5)) / 2},
+		{"log cosh", NewLogCosh(), (math.Log(math.Cosh(0.5)) + math.Log(math.Cosh(3))) / 2},
+		// under-prediction costs Q, over-prediction 1 - Q
+		{"quantile", NewQuantile(0.9), (0.1*0.5 + 0.9*3) / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.Calculate(output, target); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("loss = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// log(cosh(x)) overflows for large residuals unless computed stably.
+func TestLogCoshLargeResidual(t *testing.T) {
+	got := NewLogCosh().Calculate(mat.NewDense(1, 1, []float64{1000}), mat.NewDense(1, 1, []float64{0}))
+	if want := 1000 - math.Ln2; math.Abs(got-want) > 1e-9 {
+		t.Errorf("loss = %v, want %v", got, want)
+	}
+}
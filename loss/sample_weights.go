@@ -0,0 +1,42 @@
+package loss
+
+import "gonum.org/v1/gonum/mat"
+
+// Calculator is implemented by every loss in this package.
+type Calculator interface {
+	Calculate(output, target *mat.Dense) float64
+}
+
+// WeightedCalculate returns the mean over samples of each sample's loss
+// scaled by its weight. A nil sampleWeights falls back to f.Calculate.
+func WeightedCalculate(f Calculator, output, target *mat.Dense, sampleWeights []float64) float64 {
+	if sampleWeights == nil {
+		return f.Calculate(output, target)
+	}
+
+	r, c := output.Dims()
+	_, tc := target.Dims()
+	var total float64
+	for i := 0; i < r; i++ {
+		outRow := output.Slice(i, i+1, 0, c).(*mat.Dense)
+		targetRow := target.Slice(i, i+1, 0, tc).(*mat.Dense)
+		total += sampleWeights[i] * f.Calculate(outRow, targetRow)
+	}
+	return total / float64(r)
+}
+
+// WeightRows scales every row of grad in place by the matching sample weight.
+// A nil sampleWeights leaves grad untouched.
+func WeightRows(grad *mat.Dense, sampleWeights []float64) {
+	if sampleWeights == nil {
+		return
+	}
+
+	r, _ := grad.Dims()
+	for i := 0; i < r; i++ {
+		row := grad.RawRowView(i)
+		for j := range row {
+			row[j] *= sampleWeights[i]
+		}
+	}
+}
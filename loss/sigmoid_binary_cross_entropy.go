@@ -0,0 +1,80 @@
+package loss
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SigmoidBinaryCrossEntropy treats every output column as an independent
+// binary label, which makes it suitable for multi-label classification. It
+// works directly on logits so that large activations do not overflow.
+type SigmoidBinaryCrossEntropy struct {
+	// PosWeights optionally scales the positive term of each class. A nil
+	// slice weights every class equally.
+	PosWeights []float64
+}
+
+func NewSigmoidBinaryCrossEntropy() *SigmoidBinaryCrossEntropy {
+	return &SigmoidBinaryCrossEntropy{}
+}
+
+func NewSigmoidBinaryCrossEntropyWithPosWeights(posWeights []float64) *SigmoidBinaryCrossEntropy {
+	return &SigmoidBinaryCrossEntropy{PosWeights: posWeights}
+}
+
+func sigmoid(x float64) float64 {
+	if x >= 0 {
+		return 1 / (1 + math.Exp(-x))
+	}
+	e := math.Exp(x)
+	return e / (1 + e)
+}
+
+func (f *SigmoidBinaryCrossEntropy) posWeight(j int) float64 {
+	if f.PosWeights == nil {
+		return 1
+	}
+	return f.PosWeights[j]
+}
+
+func (f *SigmoidBinaryCrossEntropy) Transform(output *mat.Dense) *mat.Dense {
+	r, c := output.Dims()
+	result := mat.NewDense(r, c, nil)
+	result.Apply(func(_, _ int, v float64) float64 {
+		return sigmoid(v)
+	}, output)
+	return result
+}
+
+func (f *SigmoidBinaryCrossEntropy) Calculate(output, target *mat.Dense) float64 {
+	r, c := output.Dims()
+	var totalLoss float64
+
+	for i := 0; i < r; i++ {
+		oRow := output.RawRowView(i)
+		tRow := target.RawRowView(i)
+
+		for j := 0; j < c; j++ {
+			x, y := oRow[j], tRow[j]
+			// -log(sigmoid(x)) written as log(1 + e^-|x|) + max(-x, 0)
+			logSigNeg := math.Log1p(math.Exp(-math.Abs(x))) + math.Max(-x, 0)
+			totalLoss += (1-y)*x + (1+(f.posWeight(j)-1)*y)*logSigNeg
+		}
+	}
+
+	return totalLoss / float64(r*c)
+}
+
+func (f *SigmoidBinaryCrossEntropy) Derivative(output, target *mat.Dense) *mat.Dense {
+	r, c := output.Dims()
+	result := mat.NewDense(r, c, nil)
+
+	result.Apply(func(i, j int, v float64) float64 {
+		y := target.At(i, j)
+		w := f.posWeight(j)
+		return sigmoid(v)*(w*y+1-y) - w*y
+	}, output)
+
+	return result
+}
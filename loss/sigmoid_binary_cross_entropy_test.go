@@ -0,0 +1,61 @@
+package loss
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSigmoidBinaryCrossEntropyDerivative(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	output := randomDense(rng, 3, 4)
+	target := mat.NewDense(3, 4, []float64{
+		1, 0, 0, 1,
+		0, 0, 1, 1,
+		1, 1, 0, 0.5,
+	})
+
+	tests := map[string]*SigmoidBinaryCrossEntropy{
+		"unweighted":  NewSigmoidBinaryCrossEntropy(),
+		"pos weights": NewSigmoidBinaryCrossEntropyWithPosWeights([]float64{1, 2, 0.5, 3}),
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkDerivative(t, f, output, target, 3*4)
+		})
+	}
+}
+
+func TestSigmoidBinaryCrossEntropyCalculate(t *testing.T) {
+	naive := func(x, y, w float64) float64 {
+		p := 1 / (1 + math.Exp(-x))
+		return -(w*y*math.Log(p) + (1-y)*math.Log(1-p))
+	}
+
+	tests := []struct {
+		name   string
+		x, y   float64
+		weight float64
+		want   float64
+	}{
+		{"positive", 0.7, 1, 1, naive(0.7, 1, 1)},
+		{"negative", -1.2, 0, 1, naive(-1.2, 0, 1)},
+		{"pos weight", 0.3, 1, 4, naive(0.3, 1, 4)},
+		{"pos weight on negative", 0.3, 0, 4, naive(0.3, 0, 4)},
+		// a naive log(sigmoid) underflows to -Inf for these
+		{"large wrong positive", -1000, 1, 1, 1000},
+		{"large wrong negative", 1000, 0, 1, 1000},
+		{"large right", 1000, 1, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewSigmoidBinaryCrossEntropyWithPosWeights([]float64{tt.weight})
+			got := f.Calculate(mat.NewDense(1, 1, []float64{tt.x}), mat.NewDense(1, 1, []float64{tt.y}))
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("loss = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,12 +6,41 @@ import (
 	"gonum.org/v1/gonum/mat"
 )
 
-type SoftMaxCrossEntropy struct{}
+type SoftMaxCrossEntropy struct {
+	// ClassWeights optionally scales the contribution of each class, which
+	// helps with imbalanced datasets. A nil slice weights every class equally.
+	ClassWeights []float64
+	// LabelSmoothing mixes targets with the uniform distribution:
+	// t' = (1 - LabelSmoothing) * t + LabelSmoothing / classes.
+	LabelSmoothing float64
+}
 
 func NewSoftMaxCrossEntropyFunc() *SoftMaxCrossEntropy {
 	return &SoftMaxCrossEntropy{}
 }
 
+func NewWeightedSoftMaxCrossEntropy(classWeights []float64, labelSmoothing float64) *SoftMaxCrossEntropy {
+	return &SoftMaxCrossEntropy{
+		ClassWeights:   classWeights,
+		LabelSmoothing: labelSmoothing,
+	}
+}
+
+func (f *SoftMaxCrossEntropy) classWeight(j int) float64 {
+	if f.ClassWeights == nil {
+		return 1
+	}
+	return f.ClassWeights[j]
+}
+
+// smoothedTargetRow writes the label-smoothed targets of tRow into dst.
+func (f *SoftMaxCrossEntropy) smoothedTargetRow(dst, tRow []float64) {
+	c := len(tRow)
+	for j, t := range tRow {
+		dst[j] = (1-f.LabelSmoothing)*t + f.LabelSmoothing/float64(c)
+	}
+}
+
 func (f *SoftMaxCrossEntropy) Transform(output *mat.Dense) *mat.Dense {
 	r, c := output.Dims()
 	result := mat.NewDense(r, c, nil)
@@ -40,20 +69,32 @@ func (f *SoftMaxCrossEntropy) Transform(output *mat.Dense) *mat.Dense {
 	return result
 }
 
+// Calculate returns the mean over samples of -sum_j w_j * t_j * log(p_j), so
+// soft and smoothed targets are handled as well as one-hot ones.
 func (f *SoftMaxCrossEntropy) Calculate(output, target *mat.Dense) float64 {
-	probs := f.Transform(output)
-
-	r, c := probs.Dims()
+	r, c := output.Dims()
 	var totalLoss float64
-	const epsilon = 1e-15
+	targets := make([]float64, c)
 
 	for i := 0; i < r; i++ {
-		pRow := probs.RawRowView(i)
-		tRow := target.RawRowView(i)
+		row := output.RawRowView(i)
+		f.smoothedTargetRow(targets, target.RawRowView(i))
+
+		maxVal := row[0]
+		for _, v := range row {
+			if v > maxVal {
+				maxVal = v
+			}
+		}
+		sumExp := 0.0
+		for _, v := range row {
+			sumExp += math.Exp(v - maxVal)
+		}
+		logSumExp := maxVal + math.Log(sumExp)
 
-		for j := 0; j < c; j++ {
-			if tRow[j] > 0 {
-				totalLoss -= math.Log(pRow[j] + epsilon)
+		for j, t := range targets {
+			if t != 0 {
+				totalLoss -= f.classWeight(j) * t * (row[j] - logSumExp)
 			}
 		}
 	}
@@ -66,6 +107,21 @@ func (f *SoftMaxCrossEntropy) Derivative(output, target *mat.Dense) *mat.Dense {
 	r, c := probs.Dims()
 
 	result := mat.NewDense(r, c, nil)
-	result.Sub(probs, target)
+	targets := make([]float64, c)
+
+	for i := 0; i < r; i++ {
+		pRow := probs.RawRowView(i)
+		resRow := result.RawRowView(i)
+		f.smoothedTargetRow(targets, target.RawRowView(i))
+
+		// d/dx_k of -sum_j w_j t_j log(p_j) is p_k * sum_j w_j t_j - w_k t_k
+		weightedSum := 0.0
+		for j, t := range targets {
+			weightedSum += f.classWeight(j) * t
+		}
+		for k, p := range pRow {
+			resRow[k] = p*weightedSum - f.classWeight(k)*targets[k]
+		}
+	}
 	return result
 }
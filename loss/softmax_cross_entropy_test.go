@@ -0,0 +1,91 @@
+package loss
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSoftMaxCrossEntropyDerivative(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	output := randomDense(rng, 3, 4)
+	oneHot := mat.NewDense(3, 4, []float64{
+		0, 1, 0, 0,
+		1, 0, 0, 0,
+		0, 0, 0, 1,
+	})
+	soft := mat.NewDense(3, 4, []float64{
+		0.2, 0.5, 0.3, 0,
+		1, 0, 0, 0,
+		0.25, 0.25, 0.25, 0.25,
+	})
+	weights := []float64{1, 3, 0.5, 2}
+
+	tests := []struct {
+		name   string
+		f      *SoftMaxCrossEntropy
+		target *mat.Dense
+	}{
+		{"plain", NewSoftMaxCrossEntropyFunc(), oneHot},
+		{"class weights", NewWeightedSoftMaxCrossEntropy(weights, 0), oneHot},
+		{"label smoothing", NewWeightedSoftMaxCrossEntropy(nil, 0.1), oneHot},
+		{"both", NewWeightedSoftMaxCrossEntropy(weights, 0.1), oneHot},
+		{"soft targets", NewWeightedSoftMaxCrossEntropy(weights, 0.1), soft},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDerivative(t, tt.f, output, tt.target, 3)
+		})
+	}
+}
+
+func TestSoftMaxCrossEntropyCalculate(t *testing.T) {
+	output := mat.NewDense(1, 3, []float64{2, 1, 0})
+	target := mat.NewDense(1, 3, []float64{0, 1, 0})
+	logSumExp := math.Log(math.Exp(2) + math.Exp(1) + 1)
+	logP := []float64{2 - logSumExp, 1 - logSumExp, -logSumExp}
+
+	tests := []struct {
+		name string
+		f    *SoftMaxCrossEntropy
+		want float64
+	}{
+		{"plain", NewSoftMaxCrossEntropyFunc(), -logP[1]},
+		// a one-hot target only picks up the weight of its own class
+		{"class weights", NewWeightedSoftMaxCrossEntropy([]float64{5, 2, 7}, 0), -2 * logP[1]},
+		// smoothing 0.3 over 3 classes turns the target into (0.1, 0.8, 0.1)
+		{"label smoothing", NewWeightedSoftMaxCrossEntropy(nil, 0.3), -(0.1*logP[0] + 0.8*logP[1] + 0.1*logP[2])},
+		{"both", NewWeightedSoftMaxCrossEntropy([]float64{5, 2, 7}, 0.3), -(0.5*logP[0] + 1.6*logP[1] + 0.7*logP[2])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.Calculate(output, target); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("loss = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleWeights(t *testing.T) {
+	f := NewMAE()
+	output := mat.NewDense(2, 2, []float64{1, 1, 0, 0})
+	target := mat.NewDense(2, 2, []float64{0, 0, 0, 2})
+	// per-sample MAE is 1 and 1
+	weights := []float64{3, 0.5}
+
+	if got, want := WeightedCalculate(f, output, target, weights), (3*1+0.5*1)/2.0; math.Abs(got-want) > 1e-12 {
+		t.Errorf("weighted loss = %v, want %v", got, want)
+	}
+	if got, want := WeightedCalculate(f, output, target, nil), f.Calculate(output, target); got != want {
+		t.Errorf("nil weights give %v, want the unweighted %v", got, want)
+	}
+
+	grad := f.Derivative(output, target)
+	WeightRows(grad, weights)
+	want := mat.NewDense(2, 2, []float64{3, 3, 0, -0.5})
+	if !mat.EqualApprox(grad, want, 1e-12) {
+		t.Errorf("weighted gradient = %v, want %v", mat.Formatted(grad), mat.Formatted(want))
+	}
+}
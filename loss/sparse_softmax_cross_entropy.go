@@ -0,0 +1,103 @@
+package loss
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SparseSoftMaxCrossEntropy is SoftMaxCrossEntropy for integer class labels.
+// The target is a single column holding the class index of every sample, and
+// the probabilities are indexed by label without building a one-hot matrix.
+type SparseSoftMaxCrossEntropy struct {
+	SoftMaxCrossEntropy
+}
+
+func NewSparseSoftMaxCrossEntropy() *SparseSoftMaxCrossEntropy {
+	return &SparseSoftMaxCrossEntropy{}
+}
+
+func NewWeightedSparseSoftMaxCrossEntropy(classWeights []float64, labelSmoothing float64) *SparseSoftMaxCrossEntropy {
+	return &SparseSoftMaxCrossEntropy{
+		SoftMaxCrossEntropy: SoftMaxCrossEntropy{
+			ClassWeights:   classWeights,
+			LabelSmoothing: labelSmoothing,
+		},
+	}
+}
+
+// label returns the class index of sample i and panics when it is not an
+// integer in [0, classes).
+func label(labels *mat.Dense, i, classes int) int {
+	v := labels.At(i, 0)
+	if v != math.Trunc(v) || v < 0 || v >= float64(classes) {
+		panic(fmt.Sprintf("sparse softmax cross entropy: label %v of sample %d is not a class in [0, %d)", v, i, classes))
+	}
+	return int(v)
+}
+
+// weightedTargetSum returns sum_j w_j * t_j for the smoothed one-hot target of
+// class y.
+func (f *SparseSoftMaxCrossEntropy) weightedTargetSum(y, classes int) float64 {
+	total := (1 - f.LabelSmoothing) * f.classWeight(y)
+	if f.LabelSmoothing != 0 {
+		weights := 0.0
+		for j := 0; j < classes; j++ {
+			weights += f.classWeight(j)
+		}
+		total += f.LabelSmoothing / float64(classes) * weights
+	}
+	return total
+}
+
+func (f *SparseSoftMaxCrossEntropy) Calculate(output, labels *mat.Dense) float64 {
+	r, c := output.Dims()
+	var totalLoss float64
+
+	for i := 0; i < r; i++ {
+		y := label(labels, i, c)
+		row := output.RawRowView(i)
+
+		maxVal := row[0]
+		for _, v := range row {
+			if v > maxVal {
+				maxVal = v
+			}
+		}
+		sumExp := 0.0
+		for _, v := range row {
+			sumExp += math.Exp(v - maxVal)
+		}
+		logSumExp := maxVal + math.Log(sumExp)
+
+		// the smoothed target is LabelSmoothing/c everywhere plus
+		// 1-LabelSmoothing at the label
+		totalLoss -= (1 - f.LabelSmoothing) * f.classWeight(y) * (row[y] - logSumExp)
+		if f.LabelSmoothing != 0 {
+			for j, v := range row {
+				totalLoss -= f.LabelSmoothing / float64(c) * f.classWeight(j) * (v - logSumExp)
+			}
+		}
+	}
+
+	return totalLoss / float64(r)
+}
+
+func (f *SparseSoftMaxCrossEntropy) Derivative(output, labels *mat.Dense) *mat.Dense {
+	result := f.Transform(output)
+	r, c := result.Dims()
+
+	for i := 0; i < r; i++ {
+		y := label(labels, i, c)
+		resRow := result.RawRowView(i)
+
+		// p_k * sum_j w_j t_j - w_k t_k, as in SoftMaxCrossEntropy
+		weightedSum := f.weightedTargetSum(y, c)
+		for k, p := range resRow {
+			resRow[k] = p*weightedSum - f.classWeight(k)*f.LabelSmoothing/float64(c)
+		}
+		resRow[y] -= f.classWeight(y) * (1 - f.LabelSmoothing)
+	}
+	return result
+}
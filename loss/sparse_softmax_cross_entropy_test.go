@@ -0,0 +1,59 @@
+package loss
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSparseMatchesOneHot(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	output := randomDense(rng, 4, 3)
+	labels := mat.NewDense(4, 1, []float64{2, 0, 1, 2})
+	oneHot := mat.NewDense(4, 3, nil)
+	for i := 0; i < 4; i++ {
+		oneHot.Set(i, int(labels.At(i, 0)), 1)
+	}
+
+	weights := []float64{1, 2.5, 0.5}
+	tests := []struct {
+		name   string
+		sparse *SparseSoftMaxCrossEntropy
+		dense  *SoftMaxCrossEntropy
+	}{
+		{"plain", NewSparseSoftMaxCrossEntropy(), NewSoftMaxCrossEntropyFunc()},
+		{"weighted", NewWeightedSparseSoftMaxCrossEntropy(weights, 0.1), NewWeightedSoftMaxCrossEntropy(weights, 0.1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := tt.sparse.Calculate(output, labels), tt.dense.Calculate(output, oneHot); math.Abs(got-want) > 1e-12 {
+				t.Errorf("loss = %v, want %v", got, want)
+			}
+			if got, want := tt.sparse.Derivative(output, labels), tt.dense.Derivative(output, oneHot); !mat.EqualApprox(got, want, 1e-12) {
+				t.Errorf("derivative = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+			}
+		})
+	}
+}
+
+func TestSparseLabelRange(t *testing.T) {
+	f := NewSparseSoftMaxCrossEntropy()
+	output := mat.NewDense(1, 3, nil)
+	for _, l := range []float64{-1, 3, 1.5} {
+		for name, call := range map[string]func(){
+			"Calculate":  func() { f.Calculate(output, mat.NewDense(1, 1, []float64{l})) },
+			"Derivative": func() { f.Derivative(output, mat.NewDense(1, 1, []float64{l})) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s: expected a panic for label %v of 3 classes", name, l)
+					}
+				}()
+				call()
+			}()
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/velosypedno/nns/network"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Accuracy is the fraction of samples whose predicted class matches the target.
+func Accuracy(pred, target *mat.Dense) float64 {
+	predicted := predictedClasses(pred)
+	actual := targetClasses(pred, target)
+
+	correct := 0
+	for i := range predicted {
+		if predicted[i] == actual[i] {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(predicted))
+}
+
+// TopKAccuracy is the fraction of samples whose target class is among the k
+// most likely predicted classes.
+func TopKAccuracy(pred, target *mat.Dense, k int) float64 {
+	topK := network.TopK(pred, k)
+	actual := targetClasses(pred, target)
+
+	correct := 0
+	for i, candidates := range topK {
+		for _, c := range candidates {
+			if c == actual[i] {
+				correct++
+				break
+			}
+		}
+	}
+	return float64(correct) / float64(len(topK))
+}
+
+// Average selects how per-class precision, recall and F1 are combined.
+type Average int
+
+const (
+	// Macro is the unweighted mean of per-class scores.
+	Macro Average = iota
+	// Micro computes the score from counts pooled over all classes.
+	Micro
+)
+
+func Precision(pred, target *mat.Dense, avg Average) float64 {
+	cm := NewConfusionMatrix(pred, target)
+	if avg == Micro {
+		return cm.MicroPrecision()
+	}
+	return cm.MacroPrecision()
+}
+
+func Recall(pred, target *mat.Dense, avg Average) float64 {
+	cm := NewConfusionMatrix(pred, target)
+	if avg == Micro {
+		return cm.MicroRecall()
+	}
+	return cm.MacroRecall()
+}
+
+func F1(pred, target *mat.Dense, avg Average) float64 {
+	cm := NewConfusionMatrix(pred, target)
+	if avg == Micro {
+		return cm.MicroF1()
+	}
+	return cm.MacroF1()
+}
+
+func (a Average) String() string {
+	if a == Micro {
+		return "micro"
+	}
+	return "macro"
+}
+
+func NewAccuracy() Metric {
+	return New("accuracy", Accuracy)
+}
+
+func NewTopKAccuracy(k int) Metric {
+	return New(fmt.Sprintf("top_%d_accuracy", k), func(pred, target *mat.Dense) float64 {
+		return TopKAccuracy(pred, target, k)
+	})
+}
+
+func NewPrecision(avg Average) Metric {
+	return New(avg.String()+"_precision", func(pred, target *mat.Dense) float64 {
+		return Precision(pred, target, avg)
+	})
+}
+
+func NewRecall(avg Average) Metric {
+	return New(avg.String()+"_recall", func(pred, target *mat.Dense) float64 {
+		return Recall(pred, target, avg)
+	})
+}
+
+func NewF1(avg Average) Metric {
+	return New(avg.String()+"_f1", func(pred, target *mat.Dense) float64 {
+		return F1(pred, target, avg)
+	})
+}
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ConfusionMatrix counts samples by true class (rows) and predicted class
+// (columns).
+type ConfusionMatrix struct {
+	Counts *mat.Dense
+}
+
+func NewConfusionMatrix(pred, target *mat.Dense) *ConfusionMatrix {
+	classes := numClasses(pred)
+	counts := mat.NewDense(classes, classes, nil)
+
+	predicted := predictedClasses(pred)
+	actual := targetClasses(pred, target)
+	for i := range predicted {
+		counts.Set(actual[i], predicted[i], counts.At(actual[i], predicted[i])+1)
+	}
+	return &ConfusionMatrix{Counts: counts}
+}
+
+func (cm *ConfusionMatrix) String() string {
+	return fmt.Sprintf("%v", mat.Formatted(cm.Counts, mat.Squeeze()))
+}
+
+// counts returns true positives, false positives and false negatives per class.
+func (cm *ConfusionMatrix) counts() (tp, fp, fn []float64) {
+	n, _ := cm.Counts.Dims()
+	tp = make([]float64, n)
+	fp = make([]float64, n)
+	fn = make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			v := cm.Counts.At(i, j)
+			if i == j {
+				tp[i] += v
+			} else {
+				fn[i] += v
+				fp[j] += v
+			}
+		}
+	}
+	return tp, fp, fn
+}
+
+func ratio(num, den float64) float64 {
+	if den == 0 {
+		return 0
+	}
+	return num / den
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// Precision returns the precision of every class.
+func (cm *ConfusionMatrix) Precision() []float64 {
+	tp, fp, _ := cm.counts()
+	result := make([]float64, len(tp))
+	for i := range tp {
+		result[i] = ratio(tp[i], tp[i]+fp[i])
+	}
+	return result
+}
+
+// Recall returns the recall of every class.
+func (cm *ConfusionMatrix) Recall() []float64 {
+	tp, _, fn := cm.counts()
+	result := make([]float64, len(tp))
+	for i := range tp {
+		result[i] = ratio(tp[i], tp[i]+fn[i])
+	}
+	return result
+}
+
+// F1 returns the F1 score of every class.
+func (cm *ConfusionMatrix) F1() []float64 {
+	precision, recall := cm.Precision(), cm.Recall()
+	result := make([]float64, len(precision))
+	for i := range precision {
+		result[i] = ratio(2*precision[i]*recall[i], precision[i]+recall[i])
+	}
+	return result
+}
+
+func (cm *ConfusionMatrix) MacroPrecision() float64 {
+	return mean(cm.Precision())
+}
+
+func (cm *ConfusionMatrix) MacroRecall() float64 {
+	return mean(cm.Recall())
+}
+
+func (cm *ConfusionMatrix) MacroF1() float64 {
+	return mean(cm.F1())
+}
+
+func (cm *ConfusionMatrix) micro() (tp, fp, fn float64) {
+	tps, fps, fns := cm.counts()
+	for i := range tps {
+		tp += tps[i]
+		fp += fps[i]
+		fn += fns[i]
+	}
+	return tp, fp, fn
+}
+
+func (cm *ConfusionMatrix) MicroPrecision() float64 {
+	tp, fp, _ := cm.micro()
+	return ratio(tp, tp+fp)
+}
+
+func (cm *ConfusionMatrix) MicroRecall() float64 {
+	tp, _, fn := cm.micro()
+	return ratio(tp, tp+fn)
+}
+
+func (cm *ConfusionMatrix) MicroF1() float64 {
+	p, r := cm.MicroPrecision(), cm.MicroRecall()
+	return ratio(2*p*r, p+r)
+}
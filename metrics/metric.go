@@ -0,0 +1,80 @@
+// Package metrics scores network predictions against targets.
+//
+// Predictions are the output of Predict: one row per sample. Classification
+// targets may be either one-hot rows or a single column of class indices; a
+// single prediction column is treated as a binary probability thresholded at
+// 0.5.
+package metrics
+
+import (
+	"github.com/velosypedno/nns/network"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Metric is a named score that can be attached to training via WithMetrics.
+type Metric = network.Metric
+
+type metricFunc struct {
+	name string
+	fn   func(pred, target *mat.Dense) float64
+}
+
+func (m metricFunc) Name() string {
+	return m.name
+}
+
+func (m metricFunc) Compute(pred, target *mat.Dense) float64 {
+	return m.fn(pred, target)
+}
+
+// New wraps a plain scoring function as a Metric.
+func New(name string, fn func(pred, target *mat.Dense) float64) Metric {
+	return metricFunc{name: name, fn: fn}
+}
+
+// predictedClasses returns the class chosen for every prediction row.
+func predictedClasses(pred *mat.Dense) []int {
+	r, c := pred.Dims()
+	if c == 1 {
+		return thresholdColumn(pred, r)
+	}
+	return network.ArgMax(pred)
+}
+
+// targetClasses returns the true class of every target row.
+func targetClasses(pred, target *mat.Dense) []int {
+	r, c := target.Dims()
+	_, pc := pred.Dims()
+	switch {
+	case c > 1:
+		return network.ArgMax(target)
+	case pc == 1:
+		return thresholdColumn(target, r)
+	default:
+		classes := make([]int, r)
+		for i := range classes {
+			classes[i] = int(target.At(i, 0))
+		}
+		return classes
+	}
+}
+
+func thresholdColumn(m *mat.Dense, r int) []int {
+	classes := make([]int, r)
+	for i := range classes {
+		if m.At(i, 0) >= 0.5 {
+			classes[i] = 1
+		}
+	}
+	return classes
+}
+
+// numClasses is the number of distinct classes the predictions can express.
+func numClasses(pred *mat.Dense) int {
+	_, c := pred.Dims()
+	if c == 1 {
+		return 2
+	}
+	return c
+}
@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// pred picks classes 0, 0, 1, 1, 2, 0 for true classes 0, 1, 1, 2, 2, 0, so
+// the confusion matrix is
+//
+//	2 0 0
+//	1 1 0
+//	0 1 1
+var (
+	pred = mat.NewDense(6, 3, []float64{
+		0.7, 0.2, 0.1,
+		0.5, 0.3, 0.2,
+		0.1, 0.8, 0.1,
+		0.2, 0.5, 0.3,
+		0.1, 0.2, 0.7,
+		0.6, 0.1, 0.3,
+	})
+	labels = mat.NewDense(6, 1, []float64{0, 1, 1, 2, 2, 0})
+	oneHot = mat.NewDense(6, 3, []float64{
+		1, 0, 0,
+		0, 1, 0,
+		0, 1, 0,
+		0, 0, 1,
+		0, 0, 1,
+		1, 0, 0,
+	})
+)
+
+func TestClassificationMetrics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(pred, target *mat.Dense) float64
+		want float64
+	}{
+		{"accuracy", Accuracy, 4.0 / 6},
+		{"top 1", func(p, y *mat.Dense) float64 { return TopKAccuracy(p, y, 1) }, 4.0 / 6},
+		{"top 2", func(p, y *mat.Dense) float64 { return TopKAccuracy(p, y, 2) }, 1},
+		// per class precision 2/3, 1/2, 1 and recall 1, 1/2, 1/2
+		{"macro precision", func(p, y *mat.Dense) float64 { return Precision(p, y, Macro) }, 13.0 / 18},
+		{"macro recall", func(p, y *mat.Dense) float64 { return Recall(p, y, Macro) }, 2.0 / 3},
+		{"macro f1", func(p, y *mat.Dense) float64 { return F1(p, y, Macro) }, (4.0/5 + 1.0/2 + 2.0/3) / 3},
+		// pooled: 4 true positives, 2 false positives, 2 false negatives
+		{"micro precision", func(p, y *mat.Dense) float64 { return Precision(p, y, Micro) }, 2.0 / 3},
+		{"micro recall", func(p, y *mat.Dense) float64 { return Recall(p, y, Micro) }, 2.0 / 3},
+		{"micro f1", func(p, y *mat.Dense) float64 { return F1(p, y, Micro) }, 2.0 / 3},
+	}
+	targets := map[string]*mat.Dense{"labels": labels, "one-hot": oneHot}
+	for _, tt := range tests {
+		for kind, target := range targets {
+			t.Run(tt.name+" "+kind, func(t *testing.T) {
+				if got := tt.fn(pred, target); math.Abs(got-tt.want) > 1e-12 {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	tests := []struct {
+		name         string
+		pred, target *mat.Dense
+		want         *mat.Dense
+	}{
+		{"multiclass", pred, labels, mat.NewDense(3, 3, []float64{2, 0, 0, 1, 1, 0, 0, 1, 1})},
+		// a single column is a probability thresholded at 0.5
+		{
+			"binary",
+			mat.NewDense(4, 1, []float64{0.9, 0.2, 0.6, 0.4}),
+			mat.NewDense(4, 1, []float64{1, 0, 0, 1}),
+			mat.NewDense(2, 2, []float64{1, 1, 1, 1}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewConfusionMatrix(tt.pred, tt.target).Counts; !mat.Equal(got, tt.want) {
+				t.Errorf("counts\n%v\nwant\n%v", mat.Formatted(got), mat.Formatted(tt.want))
+			}
+		})
+	}
+}
+
+func TestROCAUC(t *testing.T) {
+	tests := []struct {
+		name         string
+		pred, target *mat.Dense
+		want         float64
+	}{
+		{
+			"binary",
+			mat.NewDense(4, 1, []float64{0.1, 0.4, 0.35, 0.8}),
+			mat.NewDense(4, 1, []float64{0, 0, 1, 1}),
+			0.75,
+		},
+		{
+			"ties count half",
+			mat.NewDense(2, 1, []float64{0.5, 0.5}),
+			mat.NewDense(2, 1, []float64{0, 1}),
+			0.5,
+		},
+		// class 0 separates perfectly, class 1 ranks half its pairs right and
+		// class 2 has no positives, so it is left out of the mean
+		{
+			"one vs rest",
+			mat.NewDense(4, 3, []float64{
+				0.9, 0.1, 0,
+				0.2, 0.7, 0.1,
+				0.8, 0.8, 0.1,
+				0.3, 0.6, 0.1,
+			}),
+			mat.NewDense(4, 1, []float64{0, 1, 0, 1}),
+			0.75,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ROCAUC(tt.pred, tt.target); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegressionMetrics(t *testing.T) {
+	p := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
+	target := mat.NewDense(2, 2, []float64{1, 0, 2, 4})
+
+	tests := []struct {
+		name string
+		fn   func(pred, target *mat.Dense) float64
+		want float64
+	}{
+		{"mae", MAE, 3.0 / 4},
+		{"rmse", RMSE, math.Sqrt(5.0 / 4)},
+		// column R2 of 1 - 1/0.5 and 1 - 4/8
+		{"r2", R2, (-1 + 0.5) / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(p, target); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	constant := mat.NewDense(2, 1, []float64{3, 3})
+	if got := R2(constant, constant); got != 1 {
+		t.Errorf("R2 of a perfectly predicted constant column = %v, want 1", got)
+	}
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// MAE is the mean absolute error over every output.
+func MAE(pred, target *mat.Dense) float64 {
+	r, c := pred.Dims()
+	sum := 0.0
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			sum += math.Abs(pred.At(i, j) - target.At(i, j))
+		}
+	}
+	return sum / float64(r*c)
+}
+
+// RMSE is the root mean squared error over every output.
+func RMSE(pred, target *mat.Dense) float64 {
+	r, c := pred.Dims()
+	sum := 0.0
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			d := pred.At(i, j) - target.At(i, j)
+			sum += d * d
+		}
+	}
+	return math.Sqrt(sum / float64(r*c))
+}
+
+// R2 is the coefficient of determination, averaged over output columns.
+func R2(pred, target *mat.Dense) float64 {
+	r, c := pred.Dims()
+	var total float64
+	for j := 0; j < c; j++ {
+		colMean := 0.0
+		for i := 0; i < r; i++ {
+			colMean += target.At(i, j)
+		}
+		colMean /= float64(r)
+
+		var ssRes, ssTot float64
+		for i := 0; i < r; i++ {
+			t := target.At(i, j)
+			d := pred.At(i, j) - t
+			ssRes += d * d
+			ssTot += (t - colMean) * (t - colMean)
+		}
+		if ssTot == 0 {
+			if ssRes == 0 {
+				total++
+			}
+			continue
+		}
+		total += 1 - ssRes/ssTot
+	}
+	return total / float64(c)
+}
+
+func NewMAE() Metric {
+	return New("mae", MAE)
+}
+
+func NewRMSE() Metric {
+	return New("rmse", RMSE)
+}
+
+func NewR2() Metric {
+	return New("r2", R2)
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ROCAUC returns the area under the ROC curve. A single prediction column is
+// scored as a binary problem; with several columns the one-vs-rest AUC of
+// every class that has both positive and negative samples is averaged.
+func ROCAUC(pred, target *mat.Dense) float64 {
+	r, c := pred.Dims()
+	actual := targetClasses(pred, target)
+
+	if c == 1 {
+		scores := make([]float64, r)
+		positives := make([]bool, r)
+		for i := range scores {
+			scores[i] = pred.At(i, 0)
+			positives[i] = actual[i] == 1
+		}
+		return binaryAUC(scores, positives)
+	}
+
+	var sum float64
+	var counted int
+	scores := make([]float64, r)
+	positives := make([]bool, r)
+	for k := 0; k < c; k++ {
+		for i := range scores {
+			scores[i] = pred.At(i, k)
+			positives[i] = actual[i] == k
+		}
+		auc := binaryAUC(scores, positives)
+		if auc >= 0 {
+			sum += auc
+			counted++
+		}
+	}
+	if counted == 0 {
+		return 0
+	}
+	return sum / float64(counted)
+}
+
+// binaryAUC computes the Mann-Whitney statistic with averaged ranks for ties.
+// It returns -1 when either class is absent.
+func binaryAUC(scores []float64, positives []bool) float64 {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return scores[order[a]] < scores[order[b]]
+	})
+
+	var rankSum float64
+	var nPos, nNeg float64
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && scores[order[end]] == scores[order[start]] {
+			end++
+		}
+		avgRank := float64(start+end+1) / 2
+		for _, idx := range order[start:end] {
+			if positives[idx] {
+				rankSum += avgRank
+				nPos++
+			} else {
+				nNeg++
+			}
+		}
+		start = end
+	}
+
+	if nPos == 0 || nNeg == 0 {
+		return -1
+	}
+	return (rankSum - nPos*(nPos+1)/2) / (nPos * nNeg)
+}
+
+func NewROCAUC() Metric {
+	return New("roc_auc", ROCAUC)
+}
@@ -0,0 +1,81 @@
+// Package network holds helpers shared by the mlp and cnn networks.
+package network
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LabelsColumn converts integer class labels into the single-column target
+// matrix expected by loss.SparseSoftMaxCrossEntropy.
+func LabelsColumn(labels []int) *mat.Dense {
+	data := make([]float64, len(labels))
+	for i, l := range labels {
+		data[i] = float64(l)
+	}
+	return mat.NewDense(len(labels), 1, data)
+}
+
+// ArgMax returns the column index of the largest value in every row.
+func ArgMax(m *mat.Dense) []int {
+	r, _ := m.Dims()
+	result := make([]int, r)
+	for i := 0; i < r; i++ {
+		row := m.RawRowView(i)
+		best := 0
+		for j, v := range row {
+			if v > row[best] {
+				best = j
+			}
+		}
+		result[i] = best
+	}
+	return result
+}
+
+// TopK returns, for every row, the column indices of its k largest values in
+// descending order. k is capped at the number of columns.
+func TopK(m *mat.Dense, k int) [][]int {
+	r, c := m.Dims()
+	if k > c {
+		k = c
+	}
+
+	result := make([][]int, r)
+	for i := 0; i < r; i++ {
+		row := m.RawRowView(i)
+		indices := make([]int, c)
+		for j := range indices {
+			indices[j] = j
+		}
+		sort.SliceStable(indices, func(a, b int) bool {
+			return row[indices[a]] > row[indices[b]]
+		})
+		result[i] = indices[:k]
+	}
+	return result
+}
+
+// Stack concatenates matrices with equal column counts vertically. It returns
+// nil when there is nothing to stack, as gonum has no empty matrices.
+func Stack(parts []*mat.Dense) *mat.Dense {
+	if len(parts) == 0 {
+		return nil
+	}
+	rows := 0
+	_, cols := parts[0].Dims()
+	for _, p := range parts {
+		r, _ := p.Dims()
+		rows += r
+	}
+
+	result := mat.NewDense(rows, cols, nil)
+	offset := 0
+	for _, p := range parts {
+		r, _ := p.Dims()
+		result.Slice(offset, offset+r, 0, cols).(*mat.Dense).Copy(p)
+		offset += r
+	}
+	return result
+}
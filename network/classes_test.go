@@ -0,0 +1,55 @@
+package network
+
+import (
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestClassHelpers(t *testing.T) {
+	scores := mat.NewDense(3, 4, []float64{
+		0.1, 0.7, 0.2, 0.0,
+		0.4, 0.1, 0.4, 0.1,
+		-1, -3, -2, -0.5,
+	})
+
+	if got, want := ArgMax(scores), []int{1, 0, 3}; !slices.Equal(got, want) {
+		t.Errorf("ArgMax = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		k    int
+		want [][]int
+	}{
+		{1, [][]int{{1}, {0}, {3}}},
+		// ties keep column order
+		{2, [][]int{{1, 2}, {0, 2}, {3, 0}}},
+		{10, [][]int{{1, 2, 0, 3}, {0, 2, 1, 3}, {3, 0, 2, 1}}},
+	}
+	for _, tt := range tests {
+		got := TopK(scores, tt.k)
+		if !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("TopK(%d) = %v, want %v", tt.k, got, tt.want)
+		}
+	}
+
+	labels := LabelsColumn([]int{2, 0, 1})
+	if got := labels.RawMatrix().Data; !slices.Equal(got, []float64{2, 0, 1}) {
+		t.Errorf("LabelsColumn = %v", got)
+	}
+}
+
+func TestStack(t *testing.T) {
+	if got := Stack(nil); got != nil {
+		t.Errorf("Stack(nil) = %v, want nil", got)
+	}
+
+	got := Stack([]*mat.Dense{
+		mat.NewDense(1, 2, []float64{1, 2}),
+		mat.NewDense(2, 2, []float64{3, 4, 5, 6}),
+	})
+	if want := mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}); !mat.Equal(got, want) {
+		t.Errorf("Stack = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+}
@@ -3,9 +3,15 @@ package cnn
 import (
 	"encoding/gob"
 	"fmt"
+	"math/rand/v2"
 
+	"github.com/velosypedno/nns/augment"
+	"github.com/velosypedno/nns/data"
 	"github.com/velosypedno/nns/layer"
 	"github.com/velosypedno/nns/loss"
+	"github.com/velosypedno/nns/metrics"
+	"github.com/velosypedno/nns/network"
+	"github.com/velosypedno/nns/preprocess"
 	"go.uber.org/zap"
 	"gonum.org/v1/gonum/mat"
 )
@@ -14,13 +20,55 @@ func init() {
 	gob.Register(&layer.Dense{})
 	gob.Register(&layer.Tanh{})
 	gob.Register(&layer.Conv{})
+	gob.Register(&layer.GroupedConv{})
+	gob.Register(&layer.DepthwiseSeparableConv{})
+	gob.Register(&layer.Conv1D{})
+	gob.Register(&layer.ConvTranspose{})
+	gob.Register(&layer.NearestUpsample{})
+	gob.Register(&layer.BilinearUpsample{})
 	gob.Register(&layer.MaxPool{})
+	gob.Register(&layer.AvgPool{})
+	gob.Register(&layer.MaxPool1D{})
+	gob.Register(&layer.AvgPool1D{})
+	gob.Register(&layer.GlobalAvgPool{})
+	gob.Register(&layer.GlobalMaxPool{})
+	gob.Register(&layer.AdaptiveAvgPool{})
+	gob.Register(&layer.AdaptiveMaxPool{})
 	gob.Register(&layer.ReLU{})
+	gob.Register(&layer.Sigmoid{})
+	gob.Register(&layer.LeakyReLU{})
+	gob.Register(&layer.ELU{})
+	gob.Register(&layer.SELU{})
+	gob.Register(&layer.GELU{})
+	gob.Register(&layer.Swish{})
+	gob.Register(&layer.Softplus{})
+	gob.Register(&layer.HardSigmoid{})
+	gob.Register(&layer.PReLU{})
+	gob.Register(&layer.Flatten{})
+	gob.Register(&layer.Reshape{})
+	gob.Register(&layer.Permute{})
+	gob.Register(&layer.Parallel{})
+
+	gob.Register(&preprocess.StandardScaler{})
+	gob.Register(&preprocess.MinMaxScaler{})
+	gob.Register(&preprocess.OneHotEncoder{})
+	gob.Register(&preprocess.LabelEncoder{})
+	gob.Register(&preprocess.PCA{})
 
 	gob.Register(&loss.MSE{})
+	gob.Register(&loss.MAE{})
+	gob.Register(&loss.Huber{})
+	gob.Register(&loss.LogCosh{})
+	gob.Register(&loss.Quantile{})
 	gob.Register(&loss.SoftMaxCrossEntropy{})
+	gob.Register(&loss.SparseSoftMaxCrossEntropy{})
+	gob.Register(&loss.SigmoidBinaryCrossEntropy{})
 }
 
+// CNNLayer is a layer of the convolutional stack. Unlike the classifier
+// layers, it receives the full learning rate and averages its parameter
+// gradients over the batch itself, as Conv does. Layers that sum them, such
+// as Dense and PReLU, belong in ClassifierLayers.
 type CNNLayer interface {
 	Forward(inputs *mat.Dense) *mat.Dense
 	Backward(upstreamGradient *mat.Dense, lr float64) *mat.Dense
@@ -32,15 +80,14 @@ type MLPLayer interface {
 	fmt.Stringer
 }
 
-type Loss interface {
-	Calculate(output, target *mat.Dense) float64
-	Derivative(output, target *mat.Dense) *mat.Dense
-	Transform(output *mat.Dense) *mat.Dense
-}
+type Loss = network.Loss
 
 type CNN struct {
 	ConvLayers       []CNNLayer
 	ClassifierLayers []MLPLayer
+	// Preprocessing, when set, is applied to every input before the first
+	// layer and is saved together with the network.
+	Preprocessing *preprocess.Pipeline
 
 	logger       *zap.Logger
 	logInterval  int
@@ -48,17 +95,13 @@ type CNN struct {
 	LearningRate float64
 	Loss         Loss
 	epochs       int
+	metrics      []metrics.Metric
+	rng          *rand.Rand
+	augmentation *augment.Pipeline
 }
 
 func New(convLayers []CNNLayer, classifierLayers []MLPLayer, opts ...Option) *CNN {
-	conf := &Config{
-		Logger:       zap.NewNop(),
-		LogInterval:  100,
-		BatchSize:    1,
-		Epochs:       10,
-		LearningRate: 0.01,
-		Loss:         nil,
-	}
+	conf := defaultConfig()
 
 	for _, opt := range opts {
 		opt(conf)
@@ -67,12 +110,16 @@ func New(convLayers []CNNLayer, classifierLayers []MLPLayer, opts ...Option) *CN
 	return &CNN{
 		ConvLayers:       convLayers,
 		ClassifierLayers: classifierLayers,
+		Preprocessing:    conf.Preprocessing,
 		logger:           conf.Logger,
 		logInterval:      conf.LogInterval,
 		batchSize:        conf.BatchSize,
 		epochs:           conf.Epochs,
 		LearningRate:     conf.LearningRate,
 		Loss:             conf.Loss,
+		metrics:          conf.Metrics,
+		rng:              conf.Rand,
+		augmentation:     conf.Augmentation,
 	}
 }
 
@@ -89,13 +136,45 @@ func (n *CNN) forward(inputs *mat.Dense) *mat.Dense {
 	return currInputs
 }
 
+func (n *CNN) infer(inputs *mat.Dense) *mat.Dense {
+	var currInputs = inputs
+	for _, l := range n.ConvLayers {
+		currInputs = layer.Infer(l, currInputs)
+	}
+
+	for _, l := range n.ClassifierLayers {
+		currInputs = layer.Infer(l, currInputs)
+	}
+
+	return currInputs
+}
+
+func (n *CNN) preprocess(inputs *mat.Dense) *mat.Dense {
+	if n.Preprocessing == nil {
+		return inputs
+	}
+	return n.Preprocessing.Transform(inputs)
+}
+
 func (n *CNN) Predict(inputs *mat.Dense) *mat.Dense {
-	logits := n.forward(inputs)
+	logits := n.infer(n.preprocess(inputs))
 	return n.Loss.Transform(logits)
 }
 
-func (n *CNN) backward(targets, outs *mat.Dense) {
+// PredictClasses returns the index of the most likely class for every input row.
+func (n *CNN) PredictClasses(inputs *mat.Dense) []int {
+	return network.ArgMax(n.Predict(inputs))
+}
+
+// PredictTopK returns the indices of the k most likely classes for every
+// input row, most likely first.
+func (n *CNN) PredictTopK(inputs *mat.Dense, k int) [][]int {
+	return network.TopK(n.Predict(inputs), k)
+}
+
+func (n *CNN) backward(targets, outs *mat.Dense, sampleWeights []float64) {
 	currentGradient := n.Loss.Derivative(outs, targets)
+	loss.WeightRows(currentGradient, sampleWeights)
 
 	currentBatchSize, _ := targets.Dims()
 	effectiveLR := n.LearningRate / float64(currentBatchSize)
@@ -111,19 +190,32 @@ func (n *CNN) backward(targets, outs *mat.Dense) {
 }
 
 func (n *CNN) Fit(X, Y *mat.Dense) {
+	n.FitWeighted(X, Y, nil)
+}
+
+// FitLabels trains on integer class labels instead of one-hot targets. The
+// network's loss must accept a single label column, such as
+// loss.SparseSoftMaxCrossEntropy.
+func (n *CNN) FitLabels(X *mat.Dense, labels []int) {
+	n.FitWeighted(X, network.LabelsColumn(labels), nil)
+}
+
+// FitWeighted trains like Fit but scales each sample's loss and gradient by
+// the matching entry of sampleWeights. A nil sampleWeights weights every
+// sample equally.
+func (n *CNN) FitWeighted(X, Y *mat.Dense, sampleWeights []float64) {
+	X = n.preprocess(X)
 	nSamples, nInputs := X.Dims()
 	_, nOutputs := Y.Dims()
+	if sampleWeights != nil && len(sampleWeights) != nSamples {
+		panic(fmt.Sprintf("cnn: got %d sample weights for %d samples", len(sampleWeights), nSamples))
+	}
 
-	n.logger.Info("Starting CNN training",
-		zap.Int("epochs", n.epochs),
-		zap.Int("samples", nSamples),
-		zap.Int("batch_size", n.batchSize),
-		zap.Float64("lr", n.LearningRate),
-	)
+	n.logStart(nSamples)
 
+	t := n.trainer()
 	for e := 0; e < n.epochs; e++ {
-		epochLoss := 0.0
-		numBatches := 0
+		epoch := t.NewEpochStats(e)
 
 		for i := 0; i < nSamples; i += n.batchSize {
 			end := i + n.batchSize
@@ -133,21 +225,110 @@ func (n *CNN) Fit(X, Y *mat.Dense) {
 
 			batchX := X.Slice(i, end, 0, nInputs).(*mat.Dense)
 			batchY := Y.Slice(i, end, 0, nOutputs).(*mat.Dense)
+			var batchW []float64
+			if sampleWeights != nil {
+				batchW = sampleWeights[i:end]
+			}
 
-			output := n.forward(batchX)
+			output, targets, batchLoss := t.TrainBatch(batchX, batchY, batchW)
+			epoch.Add(output, targets, batchLoss)
+		}
 
-			n.backward(batchY, output)
+		t.LogEpoch(epoch, "Epoch progress")
+	}
+	n.logger.Info("Training complete")
+}
 
-			epochLoss += n.Loss.Calculate(output, batchY)
-			numBatches++
-		}
+// FitDataset trains on batches streamed from ds. The next batch is read on a
+// background goroutine while the current one is being trained on, and the
+// samples are reshuffled every epoch when WithShuffle is set.
+func (n *CNN) FitDataset(ds data.Dataset) error {
+	n.logStart(ds.Len())
+
+	t := n.trainer()
+	for e := 0; e < n.epochs; e++ {
+		epoch := t.NewEpochStats(e)
 
-		if n.logInterval > 0 && e%n.logInterval == 0 {
-			n.logger.Info("Epoch progress",
-				zap.Int("epoch", e),
-				zap.Float64("avg_batch_loss", epochLoss/float64(numBatches)),
-			)
+		it := data.NewIterator(ds, n.batchSize, n.iteratorOptions()...)
+		for it.Next() {
+			batchX, batchY := it.Batch()
+			output, targets, batchLoss := t.TrainBatch(n.preprocess(batchX), batchY, nil)
+			epoch.Add(output, targets, batchLoss)
 		}
+		it.Close()
+		if err := it.Err(); err != nil {
+			return err
+		}
+
+		t.LogEpoch(epoch, "Epoch progress")
 	}
 	n.logger.Info("Training complete")
+	return nil
+}
+
+func (n *CNN) iteratorOptions() []data.IteratorOption {
+	if n.rng == nil {
+		return nil
+	}
+	return []data.IteratorOption{data.WithRand(n.rng)}
+}
+
+// trainer returns the training and evaluation loop shared with the other
+// networks, bound to this network's settings and layers.
+func (n *CNN) trainer() *network.Trainer {
+	return &network.Trainer{
+		Loss:         n.Loss,
+		Metrics:      n.metrics,
+		Augmentation: n.augmentation,
+		Logger:       n.logger,
+		LogInterval:  n.logInterval,
+		BatchSize:    n.batchSize,
+
+		Preprocess: n.preprocess,
+		Forward:    n.forward,
+		Infer:      n.infer,
+		Backward:   n.backward,
+		Penalty:    n.penalty,
+	}
+}
+
+// penalty returns the regularization term of all layers.
+func (n *CNN) penalty() float64 {
+	total := 0.0
+	for _, l := range n.ConvLayers {
+		total += layer.PenaltyOf(l)
+	}
+	for _, l := range n.ClassifierLayers {
+		total += layer.PenaltyOf(l)
+	}
+	return total
+}
+
+func (n *CNN) logStart(nSamples int) {
+	n.logger.Info("Starting CNN training",
+		zap.Int("epochs", n.epochs),
+		zap.Int("samples", nSamples),
+		zap.Int("batch_size", n.batchSize),
+		zap.Float64("lr", n.LearningRate),
+	)
+}
+
+// Evaluate scores the model on X and Y in inference mode, batch by batch. It
+// returns the mean loss over all samples, plus the layers' regularization
+// penalty, and every metric configured with WithMetrics or SetMetrics.
+// Weights and the caches used by training are left untouched. It panics when
+// X has no rows.
+func (n *CNN) Evaluate(X, Y *mat.Dense) network.Evaluation {
+	// an in-memory dataset only fails when it is empty
+	result, err := n.EvaluateDataset(data.NewInMemory(X, Y))
+	if err != nil {
+		panic(fmt.Sprintf("cnn: %v", err))
+	}
+	return result
+}
+
+// EvaluateDataset is Evaluate for samples streamed from ds. It fails when ds
+// is empty, since there is no loss to average.
+func (n *CNN) EvaluateDataset(ds data.Dataset) (network.Evaluation, error) {
+	return n.trainer().EvaluateDataset(ds)
 }
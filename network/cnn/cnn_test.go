@@ -0,0 +1,86 @@
+package cnn
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/velosypedno/nns/data"
+	"github.com/velosypedno/nns/layer"
+	"github.com/velosypedno/nns/loss"
+	"github.com/velosypedno/nns/metrics"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// newDoubling returns a CNN whose logits are twice its two inputs: a 1x1
+// convolution with kernel 2 followed by an identity Dense layer.
+func newDoubling(opts ...Option) *CNN {
+	conv := layer.NewConv(1, 1, 1, 1, 2)
+	conv.Kernels = mat.NewDense(1, 1, []float64{2})
+	dense := layer.NewDense(2, 2)
+	dense.Weights = mat.NewDense(2, 2, []float64{1, 0, 0, 1})
+	opts = append([]Option{WithLoss(loss.NewSoftMaxCrossEntropyFunc())}, opts...)
+	return New([]CNNLayer{conv}, []MLPLayer{dense}, opts...)
+}
+
+func TestEvaluate(t *testing.T) {
+	X := mat.NewDense(3, 2, []float64{0, 0.5, 1, 0, 0.5, 0})
+	// the logits are [0 1], [2 0] and [1 0]; the second sample is misclassified
+	Y := mat.NewDense(3, 2, []float64{0, 1, 0, 1, 1, 0})
+	wantLoss := (2*math.Log(1+math.Exp(-1)) + math.Log(1+math.Exp(2))) / 3
+	const wantAccuracy = 2.0 / 3
+
+	n, twin := newDoubling(WithBatchSize(2), WithMetrics(metrics.NewAccuracy())), newDoubling()
+	// leave training caches behind that Evaluate must not overwrite
+	cacheX := mat.NewDense(1, 2, []float64{3, 4})
+	n.forward(cacheX)
+	twin.forward(cacheX)
+
+	got := n.Evaluate(X, Y)
+	if math.Abs(got.Loss-wantLoss) > 1e-12 {
+		t.Errorf("loss = %v, want %v", got.Loss, wantLoss)
+	}
+	if math.Abs(got.Metrics["accuracy"]-wantAccuracy) > 1e-12 {
+		t.Errorf("metrics = %v, want accuracy %v", got.Metrics, wantAccuracy)
+	}
+	if !reflect.DeepEqual(n.ConvLayers, twin.ConvLayers) || !reflect.DeepEqual(n.ClassifierLayers, twin.ClassifierLayers) {
+		t.Error("Evaluate changed the layers' weights or caches")
+	}
+
+	fromDataset, err := n.EvaluateDataset(data.NewInMemory(X, Y))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromDataset, got) {
+		t.Errorf("EvaluateDataset = %v, Evaluate = %v", fromDataset, got)
+	}
+
+	if _, err := n.EvaluateDataset(data.NewInMemory(&mat.Dense{}, &mat.Dense{})); err == nil {
+		t.Error("expected an error for an empty dataset")
+	}
+}
+
+func TestSetMetricsAfterLoad(t *testing.T) {
+	saved := newDoubling(WithMetrics(metrics.NewAccuracy()))
+	// gob cannot decode the empty input cache of an unused Dense
+	saved.forward(mat.NewDense(1, 2, nil))
+	var buf bytes.Buffer
+	if err := saved.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	n, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	X, Y := mat.NewDense(1, 2, []float64{1, 0}), mat.NewDense(1, 2, []float64{1, 0})
+	if got := n.Evaluate(X, Y).Metrics; len(got) != 0 {
+		t.Errorf("loaded model reports metrics %v before SetMetrics", got)
+	}
+	n.SetMetrics(metrics.NewAccuracy())
+	if got := n.Evaluate(X, Y).Metrics["accuracy"]; got != 1 {
+		t.Errorf("accuracy = %v, want 1", got)
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"encoding/gob"
 	"io"
 	"os"
-
-	"go.uber.org/zap"
 )
 
 func (n *CNN) Save(w io.Writer) error {
@@ -20,7 +18,12 @@ func Load(r io.Reader) (*CNN, error) {
 	if err != nil {
 		return nil, err
 	}
-	n.logger = zap.NewNop()
+	// unexported settings are not serialized, so restore the defaults of New
+	conf := defaultConfig()
+	n.logger = conf.Logger
+	n.logInterval = conf.LogInterval
+	n.batchSize = conf.BatchSize
+	n.epochs = conf.Epochs
 	return &n, nil
 }
 
@@ -0,0 +1,16 @@
+package network
+
+import "gonum.org/v1/gonum/mat"
+
+// Evaluation is the result of scoring a model on a dataset.
+type Evaluation struct {
+	Loss    float64
+	Metrics map[string]float64
+}
+
+// Metric is a named score computed from a model's predictions and the
+// targets. The metrics package provides the common ones.
+type Metric interface {
+	Name() string
+	Compute(pred, target *mat.Dense) float64
+}
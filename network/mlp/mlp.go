@@ -3,10 +3,16 @@ package mlp
 import (
 	"encoding/gob"
 	"fmt"
+	"math/rand/v2"
 	"strings"
 
+	"github.com/velosypedno/nns/augment"
+	"github.com/velosypedno/nns/data"
 	"github.com/velosypedno/nns/layer"
 	"github.com/velosypedno/nns/loss"
+	"github.com/velosypedno/nns/metrics"
+	"github.com/velosypedno/nns/network"
+	"github.com/velosypedno/nns/preprocess"
 
 	"go.uber.org/zap"
 	"gonum.org/v1/gonum/mat"
@@ -15,16 +21,46 @@ import (
 func init() {
 	gob.Register(&layer.Dense{})
 	gob.Register(&layer.Tanh{})
+	gob.Register(&layer.Sigmoid{})
+	gob.Register(&layer.LeakyReLU{})
+	gob.Register(&layer.ELU{})
+	gob.Register(&layer.SELU{})
+	gob.Register(&layer.GELU{})
+	gob.Register(&layer.Swish{})
+	gob.Register(&layer.Softplus{})
+	gob.Register(&layer.HardSigmoid{})
+	gob.Register(&layer.PReLU{})
+	gob.Register(&layer.RNN{})
+	gob.Register(&layer.LSTM{})
+	gob.Register(&layer.GRU{})
+	gob.Register(&layer.Embedding{})
+	gob.Register(&layer.Flatten{})
+	gob.Register(&layer.Reshape{})
+	gob.Register(&layer.Permute{})
+	gob.Register(&layer.Parallel{})
+	gob.Register(&layer.LayerNorm{})
+	gob.Register(&layer.MultiHeadAttention{})
+	gob.Register(&layer.SinusoidalPositionalEncoding{})
+	gob.Register(&layer.LearnedPositionalEncoding{})
+	gob.Register(&layer.TransformerEncoderBlock{})
+
+	gob.Register(&preprocess.StandardScaler{})
+	gob.Register(&preprocess.MinMaxScaler{})
+	gob.Register(&preprocess.OneHotEncoder{})
+	gob.Register(&preprocess.LabelEncoder{})
+	gob.Register(&preprocess.PCA{})
 
 	gob.Register(&loss.MSE{})
+	gob.Register(&loss.MAE{})
+	gob.Register(&loss.Huber{})
+	gob.Register(&loss.LogCosh{})
+	gob.Register(&loss.Quantile{})
 	gob.Register(&loss.SoftMaxCrossEntropy{})
+	gob.Register(&loss.SparseSoftMaxCrossEntropy{})
+	gob.Register(&loss.SigmoidBinaryCrossEntropy{})
 }
 
-type Loss interface {
-	Calculate(output, target *mat.Dense) float64
-	Derivative(output, target *mat.Dense) *mat.Dense
-	Transform(output *mat.Dense) *mat.Dense
-}
+type Loss = network.Loss
 
 type Layer interface {
 	Forward(inputs *mat.Dense) *mat.Dense
@@ -36,20 +72,21 @@ type MLP struct {
 	Layers       []Layer
 	LearningRate float64
 	Loss         Loss
+	// Preprocessing, when set, is applied to every input before the first
+	// layer and is saved together with the network.
+	Preprocessing *preprocess.Pipeline
 
-	logger      *zap.Logger
-	logInterval int
-	batchSize   int
-	epochs      int
+	logger       *zap.Logger
+	logInterval  int
+	batchSize    int
+	epochs       int
+	metrics      []metrics.Metric
+	rng          *rand.Rand
+	augmentation *augment.Pipeline
 }
 
 func New(layers []Layer, lr float64, lossFunc Loss, opts ...Option) *MLP {
-	conf := &Config{
-		Logger:      zap.NewNop(),
-		LogInterval: 10000,
-		BatchSize:   1,
-		Epochs:      1000,
-	}
+	conf := defaultConfig()
 
 	for _, opt := range opts {
 		opt(conf)
@@ -59,10 +96,15 @@ func New(layers []Layer, lr float64, lossFunc Loss, opts ...Option) *MLP {
 		LearningRate: lr,
 		Loss:         lossFunc,
 
-		logger:      conf.Logger,
-		logInterval: conf.LogInterval,
-		batchSize:   conf.BatchSize,
-		epochs:      conf.Epochs,
+		Preprocessing: conf.Preprocessing,
+
+		logger:       conf.Logger,
+		logInterval:  conf.LogInterval,
+		batchSize:    conf.BatchSize,
+		epochs:       conf.Epochs,
+		metrics:      conf.Metrics,
+		rng:          conf.Rand,
+		augmentation: conf.Augmentation,
 	}
 }
 
@@ -90,13 +132,40 @@ func (n *MLP) forward(inputs *mat.Dense) *mat.Dense {
 	return currInputs
 }
 
+func (n *MLP) infer(inputs *mat.Dense) *mat.Dense {
+	var currInputs = inputs
+	for _, l := range n.Layers {
+		currInputs = layer.Infer(l, currInputs)
+	}
+	return currInputs
+}
+
+func (n *MLP) preprocess(inputs *mat.Dense) *mat.Dense {
+	if n.Preprocessing == nil {
+		return inputs
+	}
+	return n.Preprocessing.Transform(inputs)
+}
+
 func (n *MLP) Predict(inputs *mat.Dense) *mat.Dense {
-	logits := n.forward(inputs)
+	logits := n.infer(n.preprocess(inputs))
 	return n.Loss.Transform(logits)
 }
 
-func (n *MLP) backward(targets, outs *mat.Dense) {
+// PredictClasses returns the index of the most likely class for every input row.
+func (n *MLP) PredictClasses(inputs *mat.Dense) []int {
+	return network.ArgMax(n.Predict(inputs))
+}
+
+// PredictTopK returns the indices of the k most likely classes for every
+// input row, most likely first.
+func (n *MLP) PredictTopK(inputs *mat.Dense, k int) [][]int {
+	return network.TopK(n.Predict(inputs), k)
+}
+
+func (n *MLP) backward(targets, outs *mat.Dense, sampleWeights []float64) {
 	currentGradient := n.Loss.Derivative(outs, targets)
+	loss.WeightRows(currentGradient, sampleWeights)
 
 	currentBatchSize, _ := targets.Dims()
 	effectiveLR := n.LearningRate / float64(currentBatchSize)
@@ -108,19 +177,32 @@ func (n *MLP) backward(targets, outs *mat.Dense) {
 }
 
 func (n *MLP) Fit(X, Y *mat.Dense) {
+	n.FitWeighted(X, Y, nil)
+}
+
+// FitLabels trains on integer class labels instead of one-hot targets. The
+// network's loss must accept a single label column, such as
+// loss.SparseSoftMaxCrossEntropy.
+func (n *MLP) FitLabels(X *mat.Dense, labels []int) {
+	n.FitWeighted(X, network.LabelsColumn(labels), nil)
+}
+
+// FitWeighted trains like Fit but scales each sample's loss and gradient by
+// the matching entry of sampleWeights. A nil sampleWeights weights every
+// sample equally.
+func (n *MLP) FitWeighted(X, Y *mat.Dense, sampleWeights []float64) {
+	X = n.preprocess(X)
 	nSamples, nInputs := X.Dims()
 	_, nOutputs := Y.Dims()
+	if sampleWeights != nil && len(sampleWeights) != nSamples {
+		panic(fmt.Sprintf("mlp: got %d sample weights for %d samples", len(sampleWeights), nSamples))
+	}
 
-	n.logger.Info("Starting training",
-		zap.Int("epochs", n.epochs),
-		zap.Int("samples", nSamples),
-		zap.Int("batch_size", n.batchSize),
-		zap.Float64("lr", n.LearningRate),
-	)
+	n.logStart(nSamples)
 
+	t := n.trainer()
 	for e := 0; e < n.epochs; e++ {
-		epochLoss := 0.0
-		numBatches := 0
+		epoch := t.NewEpochStats(e)
 
 		for i := 0; i < nSamples; i += n.batchSize {
 			end := i + n.batchSize
@@ -130,20 +212,107 @@ func (n *MLP) Fit(X, Y *mat.Dense) {
 
 			batchX := X.Slice(i, end, 0, nInputs).(*mat.Dense)
 			batchY := Y.Slice(i, end, 0, nOutputs).(*mat.Dense)
+			var batchW []float64
+			if sampleWeights != nil {
+				batchW = sampleWeights[i:end]
+			}
 
-			output := n.forward(batchX)
-			n.backward(batchY, output)
-
-			epochLoss += n.Loss.Calculate(output, batchY)
-			numBatches++
+			output, targets, batchLoss := t.TrainBatch(batchX, batchY, batchW)
+			epoch.Add(output, targets, batchLoss)
 		}
 
-		if n.logInterval > 0 && e%n.logInterval == 0 {
-			n.logger.Info("Training progress",
-				zap.Int("epoch", e),
-				zap.Float64("avg_batch_loss", epochLoss/float64(numBatches)),
-			)
+		t.LogEpoch(epoch, "Training progress")
+	}
+	n.logger.Info("Training complete")
+}
+
+// FitDataset trains on batches streamed from ds. The next batch is read on a
+// background goroutine while the current one is being trained on, and the
+// samples are reshuffled every epoch when WithShuffle is set.
+func (n *MLP) FitDataset(ds data.Dataset) error {
+	n.logStart(ds.Len())
+
+	t := n.trainer()
+	for e := 0; e < n.epochs; e++ {
+		epoch := t.NewEpochStats(e)
+
+		it := data.NewIterator(ds, n.batchSize, n.iteratorOptions()...)
+		for it.Next() {
+			batchX, batchY := it.Batch()
+			output, targets, batchLoss := t.TrainBatch(n.preprocess(batchX), batchY, nil)
+			epoch.Add(output, targets, batchLoss)
+		}
+		it.Close()
+		if err := it.Err(); err != nil {
+			return err
 		}
+
+		t.LogEpoch(epoch, "Training progress")
 	}
 	n.logger.Info("Training complete")
+	return nil
+}
+
+func (n *MLP) iteratorOptions() []data.IteratorOption {
+	if n.rng == nil {
+		return nil
+	}
+	return []data.IteratorOption{data.WithRand(n.rng)}
+}
+
+// trainer returns the training and evaluation loop shared with the other
+// networks, bound to this network's settings and layers.
+func (n *MLP) trainer() *network.Trainer {
+	return &network.Trainer{
+		Loss:         n.Loss,
+		Metrics:      n.metrics,
+		Augmentation: n.augmentation,
+		Logger:       n.logger,
+		LogInterval:  n.logInterval,
+		BatchSize:    n.batchSize,
+
+		Preprocess: n.preprocess,
+		Forward:    n.forward,
+		Infer:      n.infer,
+		Backward:   n.backward,
+		Penalty:    n.penalty,
+	}
+}
+
+// penalty returns the regularization term of all layers.
+func (n *MLP) penalty() float64 {
+	total := 0.0
+	for _, l := range n.Layers {
+		total += layer.PenaltyOf(l)
+	}
+	return total
+}
+
+func (n *MLP) logStart(nSamples int) {
+	n.logger.Info("Starting training",
+		zap.Int("epochs", n.epochs),
+		zap.Int("samples", nSamples),
+		zap.Int("batch_size", n.batchSize),
+		zap.Float64("lr", n.LearningRate),
+	)
+}
+
+// Evaluate scores the model on X and Y in inference mode, batch by batch. It
+// returns the mean loss over all samples, plus the layers' regularization
+// penalty, and every metric configured with WithMetrics or SetMetrics.
+// Weights and the caches used by training are left untouched. It panics when
+// X has no rows.
+func (n *MLP) Evaluate(X, Y *mat.Dense) network.Evaluation {
+	// an in-memory dataset only fails when it is empty
+	result, err := n.EvaluateDataset(data.NewInMemory(X, Y))
+	if err != nil {
+		panic(fmt.Sprintf("mlp: %v", err))
+	}
+	return result
+}
+
+// EvaluateDataset is Evaluate for samples streamed from ds. It fails when ds
+// is empty, since there is no loss to average.
+func (n *MLP) EvaluateDataset(ds data.Dataset) (network.Evaluation, error) {
+	return n.trainer().EvaluateDataset(ds)
 }
@@ -0,0 +1,122 @@
+package mlp
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/velosypedno/nns/data"
+	"github.com/velosypedno/nns/layer"
+	"github.com/velosypedno/nns/loss"
+	"github.com/velosypedno/nns/metrics"
+	"github.com/velosypedno/nns/preprocess"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// newLinear returns an MLP computing [x0 + 0.5, 2*x1 - 0.5].
+func newLinear(opts ...Option) *MLP {
+	dense := layer.NewDense(2, 2)
+	dense.Weights = mat.NewDense(2, 2, []float64{1, 0, 0, 2})
+	dense.Biases = mat.NewDense(1, 2, []float64{0.5, -0.5})
+	return New([]Layer{dense}, 0.1, loss.NewMSE(), opts...)
+}
+
+func TestEvaluate(t *testing.T) {
+	X := mat.NewDense(3, 2, []float64{1, 2, 0, 1, 2, -1})
+	// the outputs are [1.5 3.5], [0.5 1.5] and [2.5 -2.5]
+	Y := mat.NewDense(3, 2, []float64{1, 3, 1, 1, 1, -2})
+	// absolute errors 0.5 0.5 0.5 0.5 1.5 0.5, split into batches of 2 and 1
+	const (
+		wantLoss = (4*0.25 + 2.25 + 0.25) / 6
+		wantMAE  = (4*0.5 + 1.5 + 0.5) / 6
+	)
+
+	n, twin := newLinear(WithBatchSize(2), WithMetrics(metrics.NewMAE())), newLinear()
+	// leave a training cache behind that Evaluate must not overwrite
+	cacheX := mat.NewDense(1, 2, []float64{3, 4})
+	n.forward(cacheX)
+	twin.forward(cacheX)
+
+	got := n.Evaluate(X, Y)
+	if math.Abs(got.Loss-wantLoss) > 1e-12 {
+		t.Errorf("loss = %v, want %v", got.Loss, wantLoss)
+	}
+	if math.Abs(got.Metrics["mae"]-wantMAE) > 1e-12 {
+		t.Errorf("metrics = %v, want mae %v", got.Metrics, wantMAE)
+	}
+	if !reflect.DeepEqual(n.Layers, twin.Layers) {
+		t.Error("Evaluate changed the layers' weights or caches")
+	}
+
+	fromDataset, err := n.EvaluateDataset(data.NewInMemory(X, Y))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromDataset, got) {
+		t.Errorf("EvaluateDataset = %v, Evaluate = %v", fromDataset, got)
+	}
+
+	if _, err := n.EvaluateDataset(data.NewInMemory(&mat.Dense{}, &mat.Dense{})); err == nil {
+		t.Error("expected an error for an empty dataset")
+	}
+}
+
+func TestSetMetricsAfterLoad(t *testing.T) {
+	saved := newLinear(WithMetrics(metrics.NewMAE()))
+	// gob cannot decode the empty input cache of an unused Dense
+	saved.forward(mat.NewDense(1, 2, nil))
+	var buf bytes.Buffer
+	if err := saved.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	n, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	X, Y := mat.NewDense(1, 2, []float64{1, 1}), mat.NewDense(1, 2, []float64{1, 1})
+	if got := n.Evaluate(X, Y).Metrics; len(got) != 0 {
+		t.Errorf("loaded model reports metrics %v before SetMetrics", got)
+	}
+	n.SetMetrics(metrics.NewMAE())
+	// the outputs are [1.5 1.5]
+	if got := n.Evaluate(X, Y).Metrics["mae"]; got != 0.5 {
+		t.Errorf("mae = %v, want 0.5", got)
+	}
+}
+
+func TestSaveLoadWithPreprocessing(t *testing.T) {
+	X := mat.NewDense(4, 3, []float64{
+		1, 100, 0,
+		2, 300, 1,
+		3, 200, 2,
+		4, 400, 1,
+	})
+	Y := mat.NewDense(4, 1, []float64{0, 1, 0.5, 1})
+
+	pipeline := preprocess.NewPipeline(preprocess.NewOneHotEncoder(2), preprocess.NewStandardScaler())
+	if err := pipeline.Fit(X); err != nil {
+		t.Fatal(err)
+	}
+	// two kept columns plus three categories
+	n := New([]Layer{layer.NewDense(5, 3), layer.NewTanh(), layer.NewDense(3, 1)}, 0.1, loss.NewMSE(),
+		WithPreprocessing(pipeline), WithEpochs(5), WithBatchSize(2))
+	n.Fit(X, Y)
+
+	var buf bytes.Buffer
+	if err := n.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Preprocessing == nil {
+		t.Fatal("the pipeline was not saved")
+	}
+	if got, want := loaded.Predict(X), n.Predict(X); !mat.Equal(got, want) {
+		t.Errorf("loaded predictions %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+}
@@ -1,16 +1,38 @@
 package mlp
 
-import "go.uber.org/zap"
+import (
+	"math/rand/v2"
+
+	"github.com/velosypedno/nns/augment"
+	"github.com/velosypedno/nns/metrics"
+	"github.com/velosypedno/nns/preprocess"
+
+	"go.uber.org/zap"
+)
 
 type Config struct {
 	Logger      *zap.Logger
 	LogInterval int
 	BatchSize   int
 	Epochs      int
+	Metrics     []metrics.Metric
+	Rand        *rand.Rand
+
+	Preprocessing *preprocess.Pipeline
+	Augmentation  *augment.Pipeline
 }
 
 type Option func(*Config)
 
+func defaultConfig() *Config {
+	return &Config{
+		Logger:      zap.NewNop(),
+		LogInterval: 10000,
+		BatchSize:   1,
+		Epochs:      1000,
+	}
+}
+
 func WithLogger(logger *zap.Logger) Option {
 	return func(c *Config) {
 		c.Logger = logger
@@ -35,6 +57,45 @@ func WithEpochs(epochs int) Option {
 	}
 }
 
+// WithShuffle makes FitDataset visit samples in a different random order
+// every epoch, drawn from a generator seeded with seed.
+func WithShuffle(seed uint64) Option {
+	return func(c *Config) {
+		c.Rand = rand.New(rand.NewPCG(seed, seed))
+	}
+}
+
+// WithPreprocessing applies an already fitted pipeline to every input during
+// training, evaluation and prediction.
+func WithPreprocessing(p *preprocess.Pipeline) Option {
+	return func(c *Config) {
+		c.Preprocessing = p
+	}
+}
+
+// WithAugmentation applies p to every training batch after preprocessing.
+// Evaluation and prediction see the inputs unaugmented.
+func WithAugmentation(p *augment.Pipeline) Option {
+	return func(c *Config) {
+		c.Augmentation = p
+	}
+}
+
+// WithMetrics reports the given metrics, computed over the epoch's training
+// batches, alongside the loss at every log interval.
+func WithMetrics(ms ...metrics.Metric) Option {
+	return func(c *Config) {
+		c.Metrics = append(c.Metrics, ms...)
+	}
+}
+
 func (n *MLP) SetLogger(l *zap.Logger) {
 	n.logger = l
 }
+
+// SetMetrics replaces the metrics reported while training and by Evaluate.
+// Metrics are not saved with the model, so a loaded model reports only the
+// loss until they are set again.
+func (n *MLP) SetMetrics(ms ...metrics.Metric) {
+	n.metrics = ms
+}
@@ -0,0 +1,148 @@
+package network
+
+import (
+	"errors"
+
+	"github.com/velosypedno/nns/augment"
+	"github.com/velosypedno/nns/data"
+	"github.com/velosypedno/nns/loss"
+
+	"go.uber.org/zap"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Loss is the loss function interface of the networks.
+type Loss interface {
+	Calculate(output, target *mat.Dense) float64
+	Derivative(output, target *mat.Dense) *mat.Dense
+	Transform(output *mat.Dense) *mat.Dense
+}
+
+// Trainer holds the parts of training and evaluation that the networks share.
+// A network fills one in from its settings, with closures over its layers,
+// whenever it trains or evaluates.
+type Trainer struct {
+	Loss         Loss
+	Metrics      []Metric
+	Augmentation *augment.Pipeline
+	Logger       *zap.Logger
+	LogInterval  int
+	BatchSize    int
+
+	// Preprocess maps raw inputs to the inputs of the first layer.
+	Preprocess func(inputs *mat.Dense) *mat.Dense
+	// Forward and Infer run the layers in training and inference mode.
+	Forward func(inputs *mat.Dense) *mat.Dense
+	Infer   func(inputs *mat.Dense) *mat.Dense
+	// Backward updates the layers from the output of the last Forward.
+	Backward func(targets, outs *mat.Dense, sampleWeights []float64)
+	// Penalty returns the regularization term of all layers.
+	Penalty func() float64
+}
+
+// TrainBatch runs one optimization step and returns the batch output, the
+// targets it was trained against, which differ from batchY when augmentation
+// mixes samples, and the loss.
+func (t *Trainer) TrainBatch(batchX, batchY *mat.Dense, batchW []float64) (*mat.Dense, *mat.Dense, float64) {
+	if t.Augmentation != nil {
+		batchX, batchY, batchW = t.Augmentation.Apply(batchX, batchY, batchW)
+	}
+	output := t.Forward(batchX)
+	batchLoss := loss.WeightedCalculate(t.Loss, output, batchY, batchW) + t.Penalty()
+	t.Backward(batchY, output, batchW)
+	return output, batchY, batchLoss
+}
+
+// EpochStats accumulates the batch losses of an epoch and, on epochs that are
+// logged with metrics, the batch predictions needed to compute them.
+type EpochStats struct {
+	epoch          int
+	logged         bool
+	collect        bool
+	loss           float64
+	numBatches     int
+	preds, targets []*mat.Dense
+	transform      func(*mat.Dense) *mat.Dense
+}
+
+// NewEpochStats starts the statistics of epoch e.
+func (t *Trainer) NewEpochStats(e int) *EpochStats {
+	logged := t.LogInterval > 0 && e%t.LogInterval == 0
+	return &EpochStats{
+		epoch:     e,
+		logged:    logged,
+		collect:   logged && len(t.Metrics) > 0,
+		transform: t.Loss.Transform,
+	}
+}
+
+// Add records a batch as returned by TrainBatch.
+func (s *EpochStats) Add(output, batchY *mat.Dense, batchLoss float64) {
+	s.loss += batchLoss
+	s.numBatches++
+	if s.collect {
+		s.preds = append(s.preds, s.transform(output))
+		s.targets = append(s.targets, batchY)
+	}
+}
+
+// LogEpoch logs the average batch loss and the metrics of s under msg when
+// the epoch falls on the log interval.
+func (t *Trainer) LogEpoch(s *EpochStats, msg string) {
+	if !s.logged {
+		return
+	}
+	fields := []zap.Field{
+		zap.Int("epoch", s.epoch),
+		zap.Float64("avg_batch_loss", s.loss/float64(s.numBatches)),
+	}
+	if s.collect && len(s.preds) > 0 {
+		pred, target := Stack(s.preds), Stack(s.targets)
+		for _, m := range t.Metrics {
+			fields = append(fields, zap.Float64(m.Name(), m.Compute(pred, target)))
+		}
+	}
+	t.Logger.Info(msg, fields...)
+}
+
+// EvaluateDataset scores the model on ds in inference mode, batch by batch.
+// It returns the mean loss over all samples, plus the penalty, and every
+// metric. It fails when ds is empty, since there is no loss to average.
+func (t *Trainer) EvaluateDataset(ds data.Dataset) (Evaluation, error) {
+	if ds.Len() == 0 {
+		return Evaluation{}, errors.New("evaluate: empty dataset")
+	}
+
+	totalLoss := 0.0
+	var preds, targets []*mat.Dense
+
+	it := data.NewIterator(ds, t.BatchSize)
+	defer it.Close()
+	for it.Next() {
+		batchX, batchY := it.Batch()
+		output := t.Infer(t.Preprocess(batchX))
+
+		batchSize, _ := batchX.Dims()
+		totalLoss += t.Loss.Calculate(output, batchY) * float64(batchSize)
+
+		if len(t.Metrics) > 0 {
+			preds = append(preds, t.Loss.Transform(output))
+			targets = append(targets, batchY)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return Evaluation{}, err
+	}
+
+	result := Evaluation{
+		Loss:    totalLoss/float64(ds.Len()) + t.Penalty(),
+		Metrics: make(map[string]float64, len(t.Metrics)),
+	}
+	if len(preds) > 0 {
+		pred, target := Stack(preds), Stack(targets)
+		for _, m := range t.Metrics {
+			result.Metrics[m.Name()] = m.Compute(pred, target)
+		}
+	}
+	return result, nil
+}
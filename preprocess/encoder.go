@@ -0,0 +1,132 @@
+package preprocess
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// distinct returns the sorted distinct values of column j of X.
+func distinct(X *mat.Dense, j int) []float64 {
+	values := mat.Col(nil, j, X)
+	sort.Float64s(values)
+	return slices.Compact(values)
+}
+
+// OneHotEncoder replaces each of Columns, which hold category codes, with one
+// indicator column per category seen during Fit. Encoded columns are appended
+// after the remaining ones in the order of Columns. Unknown categories encode
+// as all zeros.
+type OneHotEncoder struct {
+	Columns    []int
+	Categories [][]float64
+}
+
+func NewOneHotEncoder(columns ...int) *OneHotEncoder {
+	return &OneHotEncoder{Columns: columns}
+}
+
+func (e *OneHotEncoder) Fit(X *mat.Dense) error {
+	_, c := X.Dims()
+	e.Categories = make([][]float64, len(e.Columns))
+	for i, j := range e.Columns {
+		if j < 0 || j >= c {
+			return fmt.Errorf("one-hot column %d out of range [0, %d)", j, c)
+		}
+		e.Categories[i] = distinct(X, j)
+	}
+	return nil
+}
+
+func (e *OneHotEncoder) Transform(X *mat.Dense) *mat.Dense {
+	r, c := X.Dims()
+
+	var kept []int
+	for j := 0; j < c; j++ {
+		if !slices.Contains(e.Columns, j) {
+			kept = append(kept, j)
+		}
+	}
+	width := len(kept)
+	for _, cats := range e.Categories {
+		width += len(cats)
+	}
+
+	out := mat.NewDense(r, width, nil)
+	for i := 0; i < r; i++ {
+		inRow := X.RawRowView(i)
+		outRow := out.RawRowView(i)
+		for k, j := range kept {
+			outRow[k] = inRow[j]
+		}
+
+		offset := len(kept)
+		for k, j := range e.Columns {
+			if idx, ok := slices.BinarySearch(e.Categories[k], inRow[j]); ok {
+				outRow[offset+idx] = 1
+			}
+			offset += len(e.Categories[k])
+		}
+	}
+	return out
+}
+
+// LabelEncoder maps the values of Column to consecutive class indices
+// 0..len(Classes)-1, which is the form FitLabels and
+// loss.SparseSoftMaxCrossEntropy expect. Unknown values encode as -1.
+type LabelEncoder struct {
+	Column  int
+	Classes []float64
+}
+
+func NewLabelEncoder(column int) *LabelEncoder {
+	return &LabelEncoder{Column: column}
+}
+
+func (e *LabelEncoder) Fit(X *mat.Dense) error {
+	_, c := X.Dims()
+	if e.Column < 0 || e.Column >= c {
+		return fmt.Errorf("label column %d out of range [0, %d)", e.Column, c)
+	}
+	e.Classes = distinct(X, e.Column)
+	return nil
+}
+
+func (e *LabelEncoder) Transform(X *mat.Dense) *mat.Dense {
+	out := mat.DenseCopyOf(X)
+	r, _ := out.Dims()
+	for i := 0; i < r; i++ {
+		idx, ok := slices.BinarySearch(e.Classes, out.At(i, e.Column))
+		if !ok {
+			idx = -1
+		}
+		out.Set(i, e.Column, float64(idx))
+	}
+	return out
+}
+
+// Labels encodes the Column of X as integer class indices.
+func (e *LabelEncoder) Labels(X *mat.Dense) []int {
+	encoded := e.Transform(X)
+	r, _ := encoded.Dims()
+	labels := make([]int, r)
+	for i := range labels {
+		labels[i] = int(encoded.At(i, e.Column))
+	}
+	return labels
+}
+
+// Inverse maps class indices back to the original values. It fails on an
+// index that is not a class, such as the -1 of an unknown value.
+func (e *LabelEncoder) Inverse(labels []int) ([]float64, error) {
+	values := make([]float64, len(labels))
+	for i, l := range labels {
+		if l < 0 || l >= len(e.Classes) {
+			return nil, fmt.Errorf("label %d of sample %d out of range [0, %d)", l, i, len(e.Classes))
+		}
+		values[i] = e.Classes[l]
+	}
+	return values, nil
+}
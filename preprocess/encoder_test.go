@@ -0,0 +1,79 @@
+package preprocess
+
+import (
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestOneHotEncoder(t *testing.T) {
+	X := mat.NewDense(3, 3, []float64{
+		5, 1, 0.5,
+		7, 0, 1.5,
+		5, 2, 2.5,
+	})
+	e := NewOneHotEncoder(0, 1)
+	if err := e.Fit(X); err != nil {
+		t.Fatal(err)
+	}
+
+	// the kept column comes first, then categories {5, 7} and {0, 1, 2}
+	want := mat.NewDense(3, 6, []float64{
+		0.5, 1, 0, 0, 1, 0,
+		1.5, 0, 1, 1, 0, 0,
+		2.5, 1, 0, 0, 0, 1,
+	})
+	if got := e.Transform(X); !mat.Equal(got, want) {
+		t.Errorf("Transform = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+
+	unknown := mat.NewDense(1, 3, []float64{6, 1, 3.5})
+	if got, want := e.Transform(unknown), mat.NewDense(1, 6, []float64{3.5, 0, 0, 0, 1, 0}); !mat.Equal(got, want) {
+		t.Errorf("Transform(unknown) = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+
+	if err := NewOneHotEncoder(3).Fit(X); err == nil {
+		t.Error("expected an error for an out-of-range column")
+	}
+}
+
+func TestLabelEncoder(t *testing.T) {
+	X := mat.NewDense(4, 2, []float64{
+		0.1, 3,
+		0.2, 1,
+		0.3, 3,
+		0.4, 2,
+	})
+	e := NewLabelEncoder(1)
+	if err := e.Fit(X); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := e.Labels(X)
+	if want := []int{2, 0, 2, 1}; !slices.Equal(labels, want) {
+		t.Errorf("Labels = %v, want %v", labels, want)
+	}
+	values, err := e.Inverse(labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := mat.Col(nil, 1, X); !slices.Equal(values, want) {
+		t.Errorf("Inverse = %v, want %v", values, want)
+	}
+
+	// the other columns are left alone and unknown values encode as -1
+	unknown := mat.NewDense(1, 2, []float64{0.5, 9})
+	if got, want := e.Transform(unknown), mat.NewDense(1, 2, []float64{0.5, -1}); !mat.Equal(got, want) {
+		t.Errorf("Transform(unknown) = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+
+	for _, l := range []int{-1, 3} {
+		if _, err := e.Inverse([]int{0, l}); err == nil {
+			t.Errorf("expected an error for label %d of 3 classes", l)
+		}
+	}
+	if err := NewLabelEncoder(2).Fit(X); err == nil {
+		t.Error("expected an error for an out-of-range column")
+	}
+}
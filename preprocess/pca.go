@@ -0,0 +1,71 @@
+package preprocess
+
+import (
+	"errors"
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// PCA projects centered inputs onto their Components leading principal axes.
+type PCA struct {
+	Components int
+
+	Mean              []float64
+	Axes              *mat.Dense
+	ExplainedVariance []float64
+}
+
+func NewPCA(components int) *PCA {
+	return &PCA{Components: components}
+}
+
+func (p *PCA) Fit(X *mat.Dense) error {
+	r, c := X.Dims()
+	if p.Components <= 0 || p.Components > c {
+		return fmt.Errorf("pca components %d out of range [1, %d]", p.Components, c)
+	}
+	if r < 2 {
+		return errors.New("pca needs at least two samples")
+	}
+
+	p.Mean = make([]float64, c)
+	for j := range p.Mean {
+		p.Mean[j] = stat.Mean(mat.Col(nil, j, X), nil)
+	}
+
+	var cov mat.SymDense
+	stat.CovarianceMatrix(&cov, X, nil)
+
+	var eig mat.EigenSym
+	if ok := eig.Factorize(&cov, true); !ok {
+		return errors.New("pca eigendecomposition failed")
+	}
+	values := eig.Values(nil)
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+
+	// eigenvalues come in ascending order, so the leading axes are the last columns
+	p.Axes = mat.NewDense(c, p.Components, nil)
+	p.ExplainedVariance = make([]float64, p.Components)
+	for k := 0; k < p.Components; k++ {
+		src := c - 1 - k
+		p.ExplainedVariance[k] = values[src]
+		for i := 0; i < c; i++ {
+			p.Axes.Set(i, k, vectors.At(i, src))
+		}
+	}
+	return nil
+}
+
+func (p *PCA) Transform(X *mat.Dense) *mat.Dense {
+	centered := mat.DenseCopyOf(X)
+	centered.Apply(func(_, j int, v float64) float64 {
+		return v - p.Mean[j]
+	}, centered)
+
+	var out mat.Dense
+	out.Mul(centered, p.Axes)
+	return &out
+}
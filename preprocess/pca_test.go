@@ -0,0 +1,44 @@
+package preprocess
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPCA(t *testing.T) {
+	// the samples lie on the line (t, 2t) for t = -1, 0, 1, 2
+	X := mat.NewDense(4, 2, []float64{-1, -2, 0, 0, 1, 2, 2, 4})
+	p := NewPCA(2)
+	if err := p.Fit(X); err != nil {
+		t.Fatal(err)
+	}
+
+	// the variance of t is 5/3 and the axis has length sqrt(5)
+	if got := p.ExplainedVariance; math.Abs(got[0]-25.0/3) > 1e-9 || math.Abs(got[1]) > 1e-9 {
+		t.Errorf("ExplainedVariance = %v, want [%v 0]", got, 25.0/3)
+	}
+
+	// the sign of an eigenvector is arbitrary
+	sign := math.Copysign(1, p.Axes.At(0, 0))
+	got := p.Transform(X)
+	for i, tt := range []float64{-1, 0, 1, 2} {
+		want := sign * (tt - 0.5) * math.Sqrt(5)
+		if math.Abs(got.At(i, 0)-want) > 1e-9 || math.Abs(got.At(i, 1)) > 1e-9 {
+			t.Errorf("sample %d projects to %v, want [%v 0]", i, got.RawRowView(i), want)
+		}
+	}
+}
+
+func TestPCAErrors(t *testing.T) {
+	X := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
+	for _, components := range []int{0, 3} {
+		if err := NewPCA(components).Fit(X); err == nil {
+			t.Errorf("expected an error for %d components of 2 columns", components)
+		}
+	}
+	if err := NewPCA(1).Fit(mat.NewDense(1, 2, []float64{1, 2})); err == nil {
+		t.Error("expected an error for a single sample")
+	}
+}
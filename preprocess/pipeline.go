@@ -0,0 +1,48 @@
+// Package preprocess provides input transformations that are fit on training
+// data and then applied identically at inference time. A Pipeline attached to
+// a network is saved together with it.
+package preprocess
+
+import "gonum.org/v1/gonum/mat"
+
+type Transformer interface {
+	// Fit learns the transformation's parameters from X.
+	Fit(X *mat.Dense) error
+	// Transform returns a transformed copy of X.
+	Transform(X *mat.Dense) *mat.Dense
+}
+
+// Pipeline chains transformers. Each step is fit on the output of the
+// previous one.
+type Pipeline struct {
+	Steps []Transformer
+}
+
+func NewPipeline(steps ...Transformer) *Pipeline {
+	return &Pipeline{Steps: steps}
+}
+
+func (p *Pipeline) Fit(X *mat.Dense) error {
+	_, err := p.FitTransform(X)
+	return err
+}
+
+// FitTransform fits every step and returns X transformed by the whole pipeline.
+func (p *Pipeline) FitTransform(X *mat.Dense) (*mat.Dense, error) {
+	current := X
+	for _, step := range p.Steps {
+		if err := step.Fit(current); err != nil {
+			return nil, err
+		}
+		current = step.Transform(current)
+	}
+	return current, nil
+}
+
+func (p *Pipeline) Transform(X *mat.Dense) *mat.Dense {
+	current := X
+	for _, step := range p.Steps {
+		current = step.Transform(current)
+	}
+	return current
+}
@@ -0,0 +1,54 @@
+package preprocess
+
+import (
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPipeline(t *testing.T) {
+	X := mat.NewDense(3, 2, []float64{
+		20, 1,
+		10, 3,
+		30, 5,
+	})
+	labels, scaler := NewLabelEncoder(0), NewMinMaxScaler()
+	p := NewPipeline(labels, scaler)
+
+	got, err := p.FitTransform(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the scaler is fit on the encoded classes 1, 0, 2
+	want := mat.NewDense(3, 2, []float64{0.5, 0, 0, 0.5, 1, 1})
+	if !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("FitTransform = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+	if got := p.Transform(X); !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("Transform = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+
+	// the fitted label encoder still inverts the first step
+	values, err := labels.Inverse(labels.Labels(X))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := mat.Col(nil, 0, X); !slices.Equal(values, want) {
+		t.Errorf("Inverse = %v, want %v", values, want)
+	}
+
+	// unseen inputs go through the fitted steps: an unknown class encodes
+	// as -1 before scaling
+	unseen := mat.NewDense(1, 2, []float64{40, 3})
+	if got, want := p.Transform(unseen), mat.NewDense(1, 2, []float64{-0.5, 0.5}); !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("Transform(unseen) = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+}
+
+func TestPipelineFitError(t *testing.T) {
+	p := NewPipeline(NewMinMaxScaler(), NewOneHotEncoder(2))
+	if err := p.Fit(mat.NewDense(2, 2, []float64{1, 2, 3, 4})); err == nil {
+		t.Error("expected the error of the failing step")
+	}
+}
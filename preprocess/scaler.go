@@ -0,0 +1,90 @@
+package preprocess
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// StandardScaler shifts every column to zero mean and unit variance.
+type StandardScaler struct {
+	Mean []float64
+	Std  []float64
+}
+
+func NewStandardScaler() *StandardScaler {
+	return &StandardScaler{}
+}
+
+func (s *StandardScaler) Fit(X *mat.Dense) error {
+	r, c := X.Dims()
+	s.Mean = make([]float64, c)
+	s.Std = make([]float64, c)
+
+	for j := 0; j < c; j++ {
+		col := mat.Col(nil, j, X)
+		for _, v := range col {
+			s.Mean[j] += v
+		}
+		s.Mean[j] /= float64(r)
+
+		for _, v := range col {
+			d := v - s.Mean[j]
+			s.Std[j] += d * d
+		}
+		s.Std[j] = math.Sqrt(s.Std[j] / float64(r))
+	}
+	return nil
+}
+
+func (s *StandardScaler) Transform(X *mat.Dense) *mat.Dense {
+	r, c := X.Dims()
+	out := mat.NewDense(r, c, nil)
+	out.Apply(func(_, j int, v float64) float64 {
+		if s.Std[j] == 0 {
+			return v - s.Mean[j]
+		}
+		return (v - s.Mean[j]) / s.Std[j]
+	}, X)
+	return out
+}
+
+// MinMaxScaler maps every column linearly onto [0, 1] using the range seen
+// during Fit.
+type MinMaxScaler struct {
+	Min []float64
+	Max []float64
+}
+
+func NewMinMaxScaler() *MinMaxScaler {
+	return &MinMaxScaler{}
+}
+
+func (s *MinMaxScaler) Fit(X *mat.Dense) error {
+	_, c := X.Dims()
+	s.Min = make([]float64, c)
+	s.Max = make([]float64, c)
+
+	for j := 0; j < c; j++ {
+		col := mat.Col(nil, j, X)
+		s.Min[j], s.Max[j] = col[0], col[0]
+		for _, v := range col {
+			s.Min[j] = math.Min(s.Min[j], v)
+			s.Max[j] = math.Max(s.Max[j], v)
+		}
+	}
+	return nil
+}
+
+func (s *MinMaxScaler) Transform(X *mat.Dense) *mat.Dense {
+	r, c := X.Dims()
+	out := mat.NewDense(r, c, nil)
+	out.Apply(func(_, j int, v float64) float64 {
+		span := s.Max[j] - s.Min[j]
+		if span == 0 {
+			return 0
+		}
+		return (v - s.Min[j]) / span
+	}, X)
+	return out
+}
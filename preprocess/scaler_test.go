@@ -0,0 +1,49 @@
+package preprocess
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestStandardScaler(t *testing.T) {
+	X := mat.NewDense(3, 2, []float64{1, 10, 3, 10, 5, 10})
+	s := NewStandardScaler()
+	if err := s.Fit(X); err != nil {
+		t.Fatal(err)
+	}
+
+	std := math.Sqrt(8.0 / 3)
+	// the constant column is only centered
+	want := mat.NewDense(3, 2, []float64{-2 / std, 0, 0, 0, 2 / std, 0})
+	if got := s.Transform(X); !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("Transform = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+
+	// unseen inputs use the statistics of Fit
+	unseen := mat.NewDense(1, 2, []float64{7, 12})
+	if got, want := s.Transform(unseen), mat.NewDense(1, 2, []float64{4 / std, 2}); !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("Transform(unseen) = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+}
+
+func TestMinMaxScaler(t *testing.T) {
+	X := mat.NewDense(3, 2, []float64{1, 10, 3, 10, 5, 10})
+	s := NewMinMaxScaler()
+	if err := s.Fit(X); err != nil {
+		t.Fatal(err)
+	}
+
+	// the constant column maps to 0
+	want := mat.NewDense(3, 2, []float64{0, 0, 0.5, 0, 1, 0})
+	if got := s.Transform(X); !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("Transform = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+
+	// values outside the fitted range are not clipped
+	unseen := mat.NewDense(1, 2, []float64{7, 12})
+	if got, want := s.Transform(unseen), mat.NewDense(1, 2, []float64{1.5, 0}); !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("Transform(unseen) = %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+}